@@ -2,4 +2,4 @@
 
 protoc internal/greet/greetpb/greet.proto --go_out=plugins=grpc:.
 protoc internal/calculator/calculatorpb/calculator.proto --go_out=plugins=grpc:.
-protoc internal/blog/blogpb/blog.proto --go_out=plugins=grpc:.
\ No newline at end of file
+protoc -I . -I third_party/googleapis internal/blog/blogpb/blog.proto --go_out=plugins=grpc:. --grpc-gateway_out=logtostderr=true:.
\ No newline at end of file
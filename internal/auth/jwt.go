@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io/ioutil"
+	"strings"
+)
+
+// authorizationHeader is the metadata key of the bearer token
+const authorizationHeader = "authorization"
+
+// claims are the JWT claims the services understand
+type claims struct {
+	Roles []string `json:"roles,omitempty"`
+	jwt.StandardClaims
+}
+
+// Authenticator validates the bearer JWT of incoming RPCs
+type Authenticator struct {
+	// hmacKey verifies HS256, HS384 and HS512 tokens
+	hmacKey []byte
+	// rsaKey verifies RS256, RS384 and RS512 tokens
+	rsaKey *rsa.PublicKey
+}
+
+// NewAuthenticator loads the verification keys from files: hmacKeyFile holds the shared
+// secret and rsaKeyFile the PEM encoded RSA public key. Either may be empty, not both.
+func NewAuthenticator(hmacKeyFile, rsaKeyFile string) (*Authenticator, error) {
+	if hmacKeyFile == "" && rsaKeyFile == "" {
+		return nil, errors.New("no token verification key configured")
+	}
+
+	a := &Authenticator{}
+	if hmacKeyFile != "" {
+		b, err := ioutil.ReadFile(hmacKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read HMAC key: %v", err)
+		}
+		// editors like to end files with a newline, it is not part of the secret
+		a.hmacKey = []byte(strings.TrimRight(string(b), "\r\n"))
+		if len(a.hmacKey) == 0 {
+			return nil, fmt.Errorf("HMAC key file %s is empty", hmacKeyFile)
+		}
+	}
+	if rsaKeyFile != "" {
+		b, err := ioutil.ReadFile(rsaKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read RSA key: %v", err)
+		}
+		if a.rsaKey, err = jwt.ParseRSAPublicKeyFromPEM(b); err != nil {
+			return nil, fmt.Errorf("cannot parse RSA key: %v", err)
+		}
+	}
+	return a, nil
+}
+
+// Authenticate validates the bearer token in the incoming metadata of ctx and returns
+// its principal, or an UNAUTHENTICATED status error
+func (a *Authenticator) Authenticate(ctx context.Context) (*Principal, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(authorizationHeader)
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	const prefix = "bearer "
+	if len(values[0]) < len(prefix) || !strings.EqualFold(values[0][:len(prefix)], prefix) {
+		return nil, status.Error(codes.Unauthenticated, "authorization is not a bearer token")
+	}
+
+	c := &claims{}
+	if _, err := jwt.ParseWithClaims(values[0][len(prefix):], c, a.key); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+	if c.Subject == "" {
+		return nil, status.Error(codes.Unauthenticated, "invalid token: missing sub claim")
+	}
+	return &Principal{Subject: c.Subject, Roles: c.Roles}, nil
+}
+
+// key picks the verification key matching the algorithm of the token, so an
+// RSA public key can never be used as an HMAC secret
+func (a *Authenticator) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if a.hmacKey != nil {
+			return a.hmacKey, nil
+		}
+	case *jwt.SigningMethodRSA:
+		if a.rsaKey != nil {
+			return a.rsaKey, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+}
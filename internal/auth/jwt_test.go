@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/dgrijalva/jwt-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testHMACKey = "test secret"
+
+// writeKeys writes an HMAC secret and an RSA public key for NewAuthenticator
+// and returns their files and the RSA private key
+func writeKeys(t *testing.T) (hmacKeyFile, rsaKeyFile string, rsaKey *rsa.PrivateKey, rsaPEM []byte) {
+	t.Helper()
+	dir := t.TempDir()
+	hmacKeyFile = filepath.Join(dir, "hmac.key")
+	// the trailing newline is not part of the secret
+	if err := ioutil.WriteFile(hmacKeyFile, []byte(testHMACKey+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPEM = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	rsaKeyFile = filepath.Join(dir, "rsa.pub")
+	if err := ioutil.WriteFile(rsaKeyFile, rsaPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return hmacKeyFile, rsaKeyFile, rsaKey, rsaPEM
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, c claims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, c).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestAuthenticate(t *testing.T) {
+	hmacKeyFile, rsaKeyFile, rsaKey, rsaPEM := writeKeys(t)
+	both, err := NewAuthenticator(hmacKeyFile, rsaKeyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaOnly, err := NewAuthenticator("", rsaKeyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := claims{Roles: []string{"writer"}, StandardClaims: jwt.StandardClaims{
+		Subject:   "alice",
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}}
+	expired := valid
+	expired.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	noSubject := valid
+	noSubject.Subject = ""
+
+	tests := []struct {
+		name          string
+		authenticator *Authenticator
+		// authorization is the metadata sent, none when empty
+		authorization string
+		wantSubject   string
+	}{
+		{"HS256", both, "Bearer " + sign(t, jwt.SigningMethodHS256, []byte(testHMACKey), valid), "alice"},
+		{"RS256", both, "Bearer " + sign(t, jwt.SigningMethodRS256, rsaKey, valid), "alice"},
+		{"lower case scheme", both, "bearer " + sign(t, jwt.SigningMethodHS256, []byte(testHMACKey), valid), "alice"},
+		{"missing token", both, "", ""},
+		{"not a bearer token", both, "Basic YWxpY2U6c2VjcmV0", ""},
+		{"malformed token", both, "Bearer not.a.token", ""},
+		{"expired token", both, "Bearer " + sign(t, jwt.SigningMethodHS256, []byte(testHMACKey), expired), ""},
+		{"missing subject", both, "Bearer " + sign(t, jwt.SigningMethodHS256, []byte(testHMACKey), noSubject), ""},
+		{"wrong HMAC secret", both, "Bearer " + sign(t, jwt.SigningMethodHS256, []byte("guessed"), valid), ""},
+		// the public RSA key must never verify a token as an HMAC secret
+		{"RSA public key used as HMAC secret", rsaOnly, "Bearer " + sign(t, jwt.SigningMethodHS256, rsaPEM, valid), ""},
+		{"HMAC token without HMAC key", rsaOnly, "Bearer " + sign(t, jwt.SigningMethodHS256, []byte(testHMACKey), valid), ""},
+		{"unsigned token", both, "Bearer " + sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, valid), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.authorization != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(authorizationHeader, tt.authorization))
+			}
+			p, err := tt.authenticator.Authenticate(ctx)
+			if tt.wantSubject == "" {
+				if status.Code(err) != codes.Unauthenticated {
+					t.Errorf("Authenticate() = %+v, %v, want Unauthenticated", p, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if p.Subject != tt.wantSubject || len(p.Roles) != 1 || p.Roles[0] != "writer" {
+				t.Errorf("Authenticate() = %+v, want subject %s with the writer role", p, tt.wantSubject)
+			}
+		})
+	}
+}
+
+func TestNewAuthenticatorErrors(t *testing.T) {
+	hmacKeyFile, _, _, _ := writeKeys(t)
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.key")
+	if err := ioutil.WriteFile(empty, []byte("\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	notPEM := filepath.Join(dir, "rsa.pub")
+	if err := ioutil.WriteFile(notPEM, []byte("not a key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                    string
+		hmacKeyFile, rsaKeyFile string
+	}{
+		{"no key", "", ""},
+		{"missing HMAC key", filepath.Join(dir, "missing.key"), ""},
+		{"empty HMAC key", empty, ""},
+		{"invalid RSA key", hmacKeyFile, notPEM},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAuthenticator(tt.hmacKeyFile, tt.rsaKeyFile); err == nil {
+				t.Error("NewAuthenticator() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+// Package auth authenticates the callers of the gRPC services with bearer JWTs
+// and carries who they are through the request context.
+package auth
+
+import "context"
+
+// Principal is the authenticated caller of an RPC
+type Principal struct {
+	// Subject is the sub claim of the token, blogs are owned by it
+	Subject string
+	// Roles are the roles claim of the token
+	Roles []string
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying the principal
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal of the RPC, ok is false when the call was not authenticated
+func FromContext(ctx context.Context) (p *Principal, ok bool) {
+	p, ok = ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
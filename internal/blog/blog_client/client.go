@@ -2,170 +2,83 @@ package main
 
 import (
 	"context"
-	"github.com/naraycitra/grpc-go-adventure/internal/blog/blogpb"
+	"flag"
+	"fmt"
+	"github.com/naraycitra/grpc-go-adventure/internal/blog/blogclient"
+	"github.com/naraycitra/grpc-go-adventure/internal/grpcclient"
+	"github.com/naraycitra/grpc-go-adventure/internal/tlsconfig"
+	"github.com/naraycitra/grpc-go-adventure/internal/tracing"
 	"go.uber.org/zap"
-	"google.golang.org/grpc"
-	"io"
+	"os"
+	"time"
 )
 
 var sugar *zap.SugaredLogger
 
 func main() {
+	addr := flag.String("addr", "localhost:50051", "address of the blog server")
+	token := flag.String("token", os.Getenv("BLOG_TOKEN"), "bearer token sent with every call, defaults to $BLOG_TOKEN")
+	useTLS := flag.Bool("tls", false, "dial with TLS, implied by the other -tls flags")
+	var tlsCfg tlsconfig.ClientConfig
+	flag.StringVar(&tlsCfg.CAFile, "tls-ca", "", "PEM bundle of the CAs verifying the server certificate, the system roots when empty")
+	flag.StringVar(&tlsCfg.CertFile, "tls-cert", "", "PEM client certificate for mutual TLS")
+	flag.StringVar(&tlsCfg.KeyFile, "tls-key", "", "PEM private key of the client certificate")
+	flag.StringVar(&tlsCfg.ServerName, "tls-server-name", "", "host name verified in the server certificate instead of the one of -addr")
+	serviceConfig := flag.String("service-config", "", "JSON service config replacing the default timeouts, retry and hedging policies")
+	traceCfg := tracing.DefaultConfig()
+	flag.StringVar(&traceCfg.Exporter, "trace", traceCfg.Exporter, "where spans are exported: none, stdout or otlp to $OTEL_EXPORTER_OTLP_ENDPOINT")
+	flag.StringVar(&traceCfg.File, "trace-file", "", "file the stdout trace exporter appends to instead of stderr")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-addr host:port] [-token jwt] export | import [command flags]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
 	logger, _ := zap.NewDevelopment()
 	defer logger.Sync()
 	sugar = logger.Sugar()
 
 	sugar.Info("Blog client")
-	cc, err := grpc.Dial("localhost:50051", grpc.WithInsecure())
+	shutdownTracing, err := tracing.Setup(context.Background(), traceCfg, "blog_client")
 	if err != nil {
-		sugar.Errorf("Error while dialing server: %v\n", err)
-	}
-	defer cc.Close()
-
-	c := blogpb.NewBlogServiceClient(cc)
-
-	doCreateBlog(c)
-
-	//doReadBlog(c)
-
-	//doUpdateBlog(c)
-	//doDeleteBlog(c)
-	doReadListBlog(c)
-}
-
-func doCreateBlog(client blogpb.BlogServiceClient) {
-	sugar.Info("Create blog...")
-	req := &blogpb.CreateBlogRequest{
-		Blog: &blogpb.Blog{
-			AuthorId: "naraycitra",
-			Title:    "Golang is cool",
-			Content:  "Go lang is really cool programming language",
-		},
+		sugar.Fatalf("Cannot set up tracing: %v", err)
 	}
-	res, err := client.CreateBlog(context.Background(), req)
-
-	if err != nil {
-		sugar.Errorf("Failed to create blog: %v", err)
+	cfg := grpcclient.Config{Addr: *addr, Token: *token}
+	if *useTLS || tlsCfg != (tlsconfig.ClientConfig{}) {
+		cfg.TLS = &tlsCfg
 	}
-	sugar.Infof("Created blog: %v", res.GetBlog())
-}
-
-func doReadBlog(client blogpb.BlogServiceClient) {
-	sugar.Info("Read Blog")
-
-	// scenario that blog not exists
-	_, err := client.ReadBlog(context.Background(), &blogpb.ReadBlogRequest{BlogId: "123456df"})
-
-	if err != nil {
-		sugar.Errorf("Error when reading: %v", err)
-	}
-
-	// scenario that blog exists
-	req := &blogpb.ReadBlogRequest{
-		BlogId: "5e5498017b8f574de2770ba6",
-	}
-	res, err := client.ReadBlog(context.Background(), req)
-	if err != nil {
-		sugar.Error("Error when reading: %v", err)
-	}
-	data := &blogpb.ReadBlogResponse{
-		Blog: &blogpb.Blog{
-			Id:       res.GetBlog().GetId(),
-			AuthorId: res.GetBlog().GetAuthorId(),
-			Title:    res.GetBlog().GetTitle(),
-			Content:  res.GetBlog().GetContent(),
-		},
+	if *serviceConfig != "" {
+		if cfg.ServiceConfig, err = grpcclient.LoadServiceConfig(*serviceConfig); err != nil {
+			sugar.Fatalf("Cannot read the service config: %v", err)
+		}
 	}
-	sugar.Error("Blog read: %v\n", data)
-}
-
-func doUpdateBlog(client blogpb.BlogServiceClient) {
-	sugar.Info("Update Blog")
-
-	// update Blog
-	// scenario that blog not exists
-	_, err := client.UpdateBlog(context.Background(), &blogpb.UpdateBlogRequest{
-		Blog: &blogpb.Blog{
-			Id:       "123456df",
-			AuthorId: "Changed Author",
-			Title:    "My First Blog (edited)",
-			Content:  "Content of the first blog, with some awesome additions!",
-		},
-	})
-
+	c, err := blogclient.Dial(cfg)
 	if err != nil {
-		sugar.Errorf("Error when update: %v", err)
+		sugar.Fatalf("Error while dialing server: %v", err)
 	}
+	defer c.Close()
 
-	// scenario that blog exists
-	updated := &blogpb.UpdateBlogRequest{
-		Blog: &blogpb.Blog{
-			Id:       "5e5498017b8f574de2770ba6",
-			AuthorId: "Changed Author",
-			Title:    "My First Blog (edited)",
-			Content:  "Content of the first blog, with some awesome additions!",
-		},
-	}
-	res, err := client.UpdateBlog(context.Background(), updated)
-	if err != nil {
-		sugar.Errorf("Error when update: %v", err)
-	}
-	data := &blogpb.UpdateBlogResponse{
-		Blog: &blogpb.Blog{
-			Id:       res.GetBlog().GetId(),
-			AuthorId: res.GetBlog().GetAuthorId(),
-			Title:    res.GetBlog().GetTitle(),
-			Content:  res.GetBlog().GetContent(),
-		},
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
 	}
-	sugar.Infof("Blog update: %v", data)
-}
-
-func doDeleteBlog(client blogpb.BlogServiceClient) {
-	sugar.Info("Delete Blog")
-
-	// delete Blog
-	// scenario that blog not exists
-	_, err := client.DeleteBlog(context.Background(), &blogpb.DeleteBlogRequest{
-		BlogId: "123456df",
-	})
-
-	if err != nil {
-		sugar.Errorf("Error when delete: %v", err)
+	command, args := flag.Arg(0), flag.Args()[1:]
+	switch command {
+	case "export":
+		err = runExport(c, args)
+	case "import":
+		err = runImport(c, args)
+	default:
+		flag.Usage()
+		os.Exit(2)
 	}
 
-	// scenario that blog exists
-	deleted := &blogpb.DeleteBlogRequest{
-		BlogId: "5e5498017b8f574de2770ba6",
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(ctx); err != nil {
+		sugar.Errorf("Cannot flush the spans: %v", err)
 	}
-	res, err := client.DeleteBlog(context.Background(), deleted)
+	cancel()
 	if err != nil {
-		sugar.Errorf("Error when update: %v", err)
+		sugar.Fatalf("%s failed: %v", command, err)
 	}
-
-	sugar.Infof("Blog update: %v", res)
-}
-
-func doReadListBlog(client blogpb.BlogServiceClient) {
-	sugar.Info("Read list of Blog")
-
-	stream, err := client.ListBlog(context.Background(), &blogpb.ListBlogRequest{})
-
-	if err != nil {
-		sugar.Errorf("error while streaming ListBlog RPC:%v", err)
-	}
-
-	for {
-		res, err := stream.Recv()
-		if err == io.EOF {
-			sugar.Info("End of blog list")
-			break
-		}
-
-		if err != nil {
-			sugar.Errorf("something not right happened:%v", err)
-		}
-		sugar.Infof("Blog: %v", res.GetBlog())
-	}
-
 }
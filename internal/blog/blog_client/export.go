@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/naraycitra/grpc-go-adventure/internal/blog/blogpb"
+	"io"
+	"os"
+)
+
+const (
+	// formatNDJSON writes one blog per line
+	formatNDJSON = "ndjson"
+	// formatJSON writes a single JSON array of blogs
+	formatJSON = "json"
+
+	// exportPageSize is the largest page ListBlog returns
+	exportPageSize = 1000
+)
+
+// runExport dumps every blog returned by ListBlog in the proto3 JSON mapping
+func runExport(client blogpb.BlogServiceClient, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", formatNDJSON, "output format, ndjson or json")
+	output := fs.String("o", "-", "file to write the blogs to, - for stdout")
+	author := fs.String("author", "", "only export the blogs of this author")
+	showDeleted := fs.Bool("show-deleted", false, "also export the blogs in the trash, servers with authentication need -author set to the token subject")
+	progress := fs.Bool("progress", false, "log how many blogs were exported after every page")
+	fs.Parse(args)
+
+	if *format != formatNDJSON && *format != formatJSON {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	out := os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	w := bufio.NewWriter(out)
+
+	sugar.Info("Export blogs...")
+	marshaler := &jsonpb.Marshaler{}
+	if *format == formatJSON {
+		io.WriteString(w, "[")
+	}
+
+	req := &blogpb.ListBlogRequest{
+		PageSize:    exportPageSize,
+		AuthorId:    *author,
+		ShowDeleted: *showDeleted,
+	}
+	count := 0
+	for {
+		stream, err := client.ListBlog(context.Background(), req)
+		if err != nil {
+			return err
+		}
+
+		var token string
+		for {
+			res, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			token = res.GetNextPageToken()
+
+			switch {
+			case *format == formatNDJSON:
+			case count == 0:
+				io.WriteString(w, "\n")
+			default:
+				io.WriteString(w, ",\n")
+			}
+			if err := marshaler.Marshal(w, res.GetBlog()); err != nil {
+				return err
+			}
+			if *format == formatNDJSON {
+				io.WriteString(w, "\n")
+			}
+			count++
+		}
+
+		if *progress {
+			sugar.Infof("Exported %d blogs", count)
+		}
+		if token == "" {
+			break
+		}
+		req.PageToken = token
+	}
+
+	if *format == formatJSON {
+		io.WriteString(w, "\n]\n")
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	sugar.Infof("Exported %d blogs", count)
+	return nil
+}
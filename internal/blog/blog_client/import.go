@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/naraycitra/grpc-go-adventure/internal/blog/blogpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"os"
+)
+
+const (
+	// idsPreserve creates the blogs with the ids of the dump
+	idsPreserve = "preserve"
+	// idsRegenerate lets the server generate new ids
+	idsRegenerate = "regenerate"
+
+	// maxLineSize is the longest NDJSON line, so the largest blog, import accepts
+	maxLineSize = 16 << 20
+)
+
+// blogReader returns the blogs of a dump one by one and io.EOF after the last one
+type blogReader interface {
+	next() (*blogpb.Blog, error)
+}
+
+// ndjsonReader reads one blog per line, skipping empty lines
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newNDJSONReader(r io.Reader) *ndjsonReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+	return &ndjsonReader{scanner: scanner}
+}
+
+func (r *ndjsonReader) next() (*blogpb.Blog, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		blog := &blogpb.Blog{}
+		if err := jsonpb.Unmarshal(bytes.NewReader(line), blog); err != nil {
+			return nil, fmt.Errorf("line %d: %v", r.line, err)
+		}
+		return blog, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// jsonReader reads the blogs of a JSON array without loading the whole array at once
+type jsonReader struct {
+	decoder *json.Decoder
+	started bool
+	index   int
+}
+
+func newJSONReader(r io.Reader) *jsonReader {
+	return &jsonReader{decoder: json.NewDecoder(r)}
+}
+
+func (r *jsonReader) next() (*blogpb.Blog, error) {
+	if !r.started {
+		tok, err := r.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+			return nil, fmt.Errorf("expected a JSON array of blogs")
+		}
+		r.started = true
+	}
+	if !r.decoder.More() {
+		return nil, io.EOF
+	}
+
+	var raw json.RawMessage
+	if err := r.decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	blog := &blogpb.Blog{}
+	if err := jsonpb.Unmarshal(bytes.NewReader(raw), blog); err != nil {
+		return nil, fmt.Errorf("blog %d: %v", r.index, err)
+	}
+	r.index++
+	return blog, nil
+}
+
+// importStats counts what happened to the blogs read from the dump
+type importStats struct {
+	read, created, skipped, failed int
+}
+
+// runImport creates the blogs of a dump written by export with BatchCreateBlogs.
+// The server sets new create and update times, and the blogs that were in the
+// trash when exported are skipped rather than brought back to life.
+func runImport(client blogpb.BlogServiceClient, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	format := fs.String("format", formatNDJSON, "input format, ndjson or json")
+	input := fs.String("i", "-", "file to read the blogs from, - for stdin")
+	ids := fs.String("ids", idsPreserve, "preserve the ids of the dump or regenerate them")
+	skipDuplicates := fs.Bool("skip-duplicates", false,
+		"skip the blogs that already exist instead of failing: with -ids preserve a blog with the same id, "+
+			"with -ids regenerate as many blogs as there are live blogs with the same author, title and content")
+	batch := fs.Int("batch", 100, "number of blogs sent per BatchCreateBlogs call")
+	progress := fs.Bool("progress", false, "log the import counters after every batch")
+	fs.Parse(args)
+
+	if *ids != idsPreserve && *ids != idsRegenerate {
+		return fmt.Errorf("unknown ids mode %q", *ids)
+	}
+	if *batch < 1 {
+		return fmt.Errorf("batch must be at least 1")
+	}
+
+	in := os.Stdin
+	if *input != "-" {
+		f, err := os.Open(*input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var reader blogReader
+	switch *format {
+	case formatNDJSON:
+		reader = newNDJSONReader(in)
+	case formatJSON:
+		reader = newJSONReader(in)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	sugar.Info("Import blogs...")
+	stats := &importStats{}
+	preserve := *ids == idsPreserve
+	// without ids to compare, the existing blogs are told apart by their content
+	var existing map[blogContent]int
+	if !preserve && *skipDuplicates {
+		var err error
+		if existing, err = liveBlogContents(client); err != nil {
+			return err
+		}
+	}
+	var blogs []*blogpb.Blog
+	for done := false; !done; {
+		blog, err := reader.next()
+		if err == io.EOF {
+			done = true
+		} else if err != nil {
+			return err
+		} else {
+			stats.read++
+			if blog.GetDeleteTime() != nil {
+				stats.skipped++
+				continue
+			}
+			if key := contentOf(blog); existing[key] > 0 {
+				// every existing blog matches a single blog of the dump
+				existing[key]--
+				stats.skipped++
+				continue
+			}
+			blogs = append(blogs, blog)
+		}
+
+		if len(blogs) == *batch || done && len(blogs) > 0 {
+			if err := importBatch(client, blogs, preserve, *skipDuplicates, stats); err != nil {
+				return err
+			}
+			blogs = blogs[:0]
+			if *progress {
+				sugar.Infof("Read %d blogs: %d created, %d skipped, %d failed",
+					stats.read, stats.created, stats.skipped, stats.failed)
+			}
+		}
+	}
+
+	sugar.Infof("Imported %d blogs: %d created, %d skipped, %d failed",
+		stats.read, stats.created, stats.skipped, stats.failed)
+	if stats.failed > 0 {
+		return fmt.Errorf("%d blogs could not be imported", stats.failed)
+	}
+	return nil
+}
+
+// importBatch creates the blogs with one BatchCreateBlogs call and counts the results
+func importBatch(client blogpb.BlogServiceClient, blogs []*blogpb.Blog, preserve, skipDuplicates bool, stats *importStats) error {
+	stream, err := client.BatchCreateBlogs(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, blog := range blogs {
+		req := &blogpb.BatchCreateBlogsRequest{
+			Blog:        blog,
+			PreserveIds: preserve,
+		}
+		if err := stream.Send(req); err != nil {
+			break
+		}
+	}
+	// a failed Send surfaces the real error in CloseAndRecv
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+
+	for _, result := range res.GetResults() {
+		st := status.FromProto(result.GetStatus())
+		switch {
+		case st.Code() == codes.OK:
+			stats.created++
+		case st.Code() == codes.AlreadyExists && skipDuplicates:
+			stats.skipped++
+		default:
+			stats.failed++
+			if i := result.GetIndex(); i >= 0 && int(i) < len(blogs) {
+				sugar.Errorf("Cannot import blog %q: %v", blogs[i].GetId(), st.Err())
+			} else {
+				sugar.Errorf("Cannot import a blog, the server answered with the invalid index %d: %v", i, st.Err())
+			}
+		}
+	}
+	return nil
+}
+
+// blogContent identifies a blog by what import keeps of it when regenerating ids
+type blogContent struct {
+	authorID, title string
+	// sum is the SHA-256 of the content, the contents may be large
+	sum [sha256.Size]byte
+}
+
+func contentOf(blog *blogpb.Blog) blogContent {
+	return blogContent{
+		authorID: blog.GetAuthorId(),
+		title:    blog.GetTitle(),
+		sum:      sha256.Sum256([]byte(blog.GetContent())),
+	}
+}
+
+// liveBlogContents counts the live blogs of the server by content with a single listing
+func liveBlogContents(client blogpb.BlogServiceClient) (map[blogContent]int, error) {
+	contents := make(map[blogContent]int)
+	req := &blogpb.ListBlogRequest{PageSize: exportPageSize}
+	for {
+		stream, err := client.ListBlog(context.Background(), req)
+		if err != nil {
+			return nil, err
+		}
+
+		var token string
+		for {
+			res, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			token = res.GetNextPageToken()
+			contents[contentOf(res.GetBlog())]++
+		}
+
+		if token == "" {
+			return contents, nil
+		}
+		req.PageToken = token
+	}
+}
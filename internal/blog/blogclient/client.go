@@ -0,0 +1,81 @@
+// Package blogclient connects to the BlogService with its service config.
+package blogclient
+
+import (
+	"github.com/naraycitra/grpc-go-adventure/internal/blog/blogpb"
+	"github.com/naraycitra/grpc-go-adventure/internal/grpcclient"
+	"google.golang.org/grpc"
+)
+
+// ServiceConfig hedges ReadBlog and retries the other reads that found the server
+// unavailable. The writes are not retried since they may have been applied, and
+// the streams without a timeout may take as long as their callers want.
+const ServiceConfig = `{
+  "methodConfig": [
+    {
+      "name": [{"service": "blog.BlogService", "method": "ReadBlog"}],
+      "timeout": "5s",
+      "hedgingPolicy": {
+        "maxAttempts": 3,
+        "hedgingDelay": "0.1s",
+        "nonFatalStatusCodes": ["UNAVAILABLE"]
+      }
+    },
+    {
+      "name": [{"service": "blog.BlogService", "method": "GetBlogRevision"}],
+      "timeout": "5s",
+      "retryPolicy": {
+        "maxAttempts": 3,
+        "initialBackoff": "0.1s",
+        "maxBackoff": "1s",
+        "backoffMultiplier": 2,
+        "retryableStatusCodes": ["UNAVAILABLE"]
+      }
+    },
+    {
+      "name": [
+        {"service": "blog.BlogService", "method": "ListBlog"},
+        {"service": "blog.BlogService", "method": "ListDeletedBlogs"},
+        {"service": "blog.BlogService", "method": "SearchBlogs"},
+        {"service": "blog.BlogService", "method": "ListBlogRevisions"}
+      ],
+      "retryPolicy": {
+        "maxAttempts": 3,
+        "initialBackoff": "0.1s",
+        "maxBackoff": "1s",
+        "backoffMultiplier": 2,
+        "retryableStatusCodes": ["UNAVAILABLE"]
+      }
+    },
+    {
+      "name": [
+        {"service": "blog.BlogService", "method": "CreateBlog"},
+        {"service": "blog.BlogService", "method": "UpdateBlog"},
+        {"service": "blog.BlogService", "method": "DeleteBlog"},
+        {"service": "blog.BlogService", "method": "UndeleteBlog"},
+        {"service": "blog.BlogService", "method": "RestoreBlogRevision"}
+      ],
+      "timeout": "10s"
+    }
+  ]
+}`
+
+// Client is a BlogService client owning its connection
+type Client struct {
+	blogpb.BlogServiceClient
+	conn *grpc.ClientConn
+}
+
+// Dial connects to the BlogService of cfg.Addr
+func Dial(cfg grpcclient.Config) (*Client, error) {
+	conn, err := grpcclient.Dial(cfg, ServiceConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{BlogServiceClient: blogpb.NewBlogServiceClient(conn), conn: conn}, nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
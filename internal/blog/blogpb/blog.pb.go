@@ -7,9 +7,13 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	status "google.golang.org/genproto/googleapis/rpc/status"
+	field_mask "google.golang.org/genproto/protobuf/field_mask"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
-	status "google.golang.org/grpc/status"
+	status1 "google.golang.org/grpc/status"
 	math "math"
 )
 
@@ -24,14 +28,114 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type SortOrder int32
+
+const (
+	// oldest blogs first
+	SortOrder_ASCENDING SortOrder = 0
+	// newest blogs first
+	SortOrder_DESCENDING SortOrder = 1
+)
+
+var SortOrder_name = map[int32]string{
+	0: "ASCENDING",
+	1: "DESCENDING",
+}
+
+var SortOrder_value = map[string]int32{
+	"ASCENDING":  0,
+	"DESCENDING": 1,
+}
+
+func (x SortOrder) String() string {
+	return proto.EnumName(SortOrder_name, int32(x))
+}
+
+func (SortOrder) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{0}
+}
+
+type OrderBy int32
+
+const (
+	// creation order
+	OrderBy_ORDER_BY_ID          OrderBy = 0
+	OrderBy_ORDER_BY_CREATE_TIME OrderBy = 1
+	// most recently edited blogs last, or first when DESCENDING
+	OrderBy_ORDER_BY_UPDATE_TIME OrderBy = 2
+)
+
+var OrderBy_name = map[int32]string{
+	0: "ORDER_BY_ID",
+	1: "ORDER_BY_CREATE_TIME",
+	2: "ORDER_BY_UPDATE_TIME",
+}
+
+var OrderBy_value = map[string]int32{
+	"ORDER_BY_ID":          0,
+	"ORDER_BY_CREATE_TIME": 1,
+	"ORDER_BY_UPDATE_TIME": 2,
+}
+
+func (x OrderBy) String() string {
+	return proto.EnumName(OrderBy_name, int32(x))
+}
+
+func (OrderBy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{1}
+}
+
+type BlogEventType int32
+
+const (
+	BlogEventType_BLOG_EVENT_TYPE_UNSPECIFIED BlogEventType = 0
+	BlogEventType_CREATED                     BlogEventType = 1
+	// also sent when a blog is taken out of the trash or a revision is restored
+	BlogEventType_UPDATED BlogEventType = 2
+	// the blog was moved to the trash
+	BlogEventType_DELETED BlogEventType = 3
+)
+
+var BlogEventType_name = map[int32]string{
+	0: "BLOG_EVENT_TYPE_UNSPECIFIED",
+	1: "CREATED",
+	2: "UPDATED",
+	3: "DELETED",
+}
+
+var BlogEventType_value = map[string]int32{
+	"BLOG_EVENT_TYPE_UNSPECIFIED": 0,
+	"CREATED":                     1,
+	"UPDATED":                     2,
+	"DELETED":                     3,
+}
+
+func (x BlogEventType) String() string {
+	return proto.EnumName(BlogEventType_name, int32(x))
+}
+
+func (BlogEventType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{2}
+}
+
 type Blog struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	AuthorId             string   `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
-	Title                string   `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
-	Content              string   `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// set by the server to the subject of the bearer token when authentication is enabled
+	AuthorId string `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Title    string `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Content  string `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	// changes on every update, set by the server. Send it back on update or delete
+	// to make the call fail with ABORTED if someone else changed the blog meanwhile
+	Etag string `protobuf:"bytes,5,opt,name=etag,proto3" json:"etag,omitempty"`
+	// set by the server while the blog is in the trash
+	DeleteTime *timestamp.Timestamp `protobuf:"bytes,6,opt,name=delete_time,json=deleteTime,proto3" json:"delete_time,omitempty"`
+	// set by the server when the blog is created, client values are ignored
+	CreateTime *timestamp.Timestamp `protobuf:"bytes,7,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// set by the server on every update, client values are ignored
+	UpdateTime           *timestamp.Timestamp `protobuf:"bytes,8,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *Blog) Reset()         { *m = Blog{} }
@@ -87,6 +191,34 @@ func (m *Blog) GetContent() string {
 	return ""
 }
 
+func (m *Blog) GetEtag() string {
+	if m != nil {
+		return m.Etag
+	}
+	return ""
+}
+
+func (m *Blog) GetDeleteTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.DeleteTime
+	}
+	return nil
+}
+
+func (m *Blog) GetCreateTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreateTime
+	}
+	return nil
+}
+
+func (m *Blog) GetUpdateTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.UpdateTime
+	}
+	return nil
+}
+
 type CreateBlogRequest struct {
 	Blog                 *Blog    `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -165,8 +297,166 @@ func (m *CreateBlogResponse) GetBlog() *Blog {
 	return nil
 }
 
+type BatchCreateBlogsRequest struct {
+	Blog *Blog `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	// create no blog at all when any of them fails, only read from the first message
+	AllOrNothing bool `protobuf:"varint,2,opt,name=all_or_nothing,json=allOrNothing,proto3" json:"all_or_nothing,omitempty"`
+	// keep blog.id instead of generating a new id, only read from the first message.
+	// A blog whose id is taken gets ALREADY_EXISTS.
+	PreserveIds          bool     `protobuf:"varint,3,opt,name=preserve_ids,json=preserveIds,proto3" json:"preserve_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchCreateBlogsRequest) Reset()         { *m = BatchCreateBlogsRequest{} }
+func (m *BatchCreateBlogsRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchCreateBlogsRequest) ProtoMessage()    {}
+func (*BatchCreateBlogsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{3}
+}
+
+func (m *BatchCreateBlogsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchCreateBlogsRequest.Unmarshal(m, b)
+}
+func (m *BatchCreateBlogsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchCreateBlogsRequest.Marshal(b, m, deterministic)
+}
+func (m *BatchCreateBlogsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchCreateBlogsRequest.Merge(m, src)
+}
+func (m *BatchCreateBlogsRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchCreateBlogsRequest.Size(m)
+}
+func (m *BatchCreateBlogsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchCreateBlogsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchCreateBlogsRequest proto.InternalMessageInfo
+
+func (m *BatchCreateBlogsRequest) GetBlog() *Blog {
+	if m != nil {
+		return m.Blog
+	}
+	return nil
+}
+
+func (m *BatchCreateBlogsRequest) GetAllOrNothing() bool {
+	if m != nil {
+		return m.AllOrNothing
+	}
+	return false
+}
+
+func (m *BatchCreateBlogsRequest) GetPreserveIds() bool {
+	if m != nil {
+		return m.PreserveIds
+	}
+	return false
+}
+
+type BatchCreateBlogsResult struct {
+	// position of the blog in the request stream, starting at 0
+	Index int32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// the created blog with its id, unset when status is not OK
+	Blog                 *Blog          `protobuf:"bytes,2,opt,name=blog,proto3" json:"blog,omitempty"`
+	Status               *status.Status `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *BatchCreateBlogsResult) Reset()         { *m = BatchCreateBlogsResult{} }
+func (m *BatchCreateBlogsResult) String() string { return proto.CompactTextString(m) }
+func (*BatchCreateBlogsResult) ProtoMessage()    {}
+func (*BatchCreateBlogsResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{4}
+}
+
+func (m *BatchCreateBlogsResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchCreateBlogsResult.Unmarshal(m, b)
+}
+func (m *BatchCreateBlogsResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchCreateBlogsResult.Marshal(b, m, deterministic)
+}
+func (m *BatchCreateBlogsResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchCreateBlogsResult.Merge(m, src)
+}
+func (m *BatchCreateBlogsResult) XXX_Size() int {
+	return xxx_messageInfo_BatchCreateBlogsResult.Size(m)
+}
+func (m *BatchCreateBlogsResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchCreateBlogsResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchCreateBlogsResult proto.InternalMessageInfo
+
+func (m *BatchCreateBlogsResult) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *BatchCreateBlogsResult) GetBlog() *Blog {
+	if m != nil {
+		return m.Blog
+	}
+	return nil
+}
+
+func (m *BatchCreateBlogsResult) GetStatus() *status.Status {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+type BatchCreateBlogsResponse struct {
+	// one result per received blog, in request order
+	Results              []*BatchCreateBlogsResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *BatchCreateBlogsResponse) Reset()         { *m = BatchCreateBlogsResponse{} }
+func (m *BatchCreateBlogsResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchCreateBlogsResponse) ProtoMessage()    {}
+func (*BatchCreateBlogsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{5}
+}
+
+func (m *BatchCreateBlogsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchCreateBlogsResponse.Unmarshal(m, b)
+}
+func (m *BatchCreateBlogsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchCreateBlogsResponse.Marshal(b, m, deterministic)
+}
+func (m *BatchCreateBlogsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchCreateBlogsResponse.Merge(m, src)
+}
+func (m *BatchCreateBlogsResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchCreateBlogsResponse.Size(m)
+}
+func (m *BatchCreateBlogsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchCreateBlogsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchCreateBlogsResponse proto.InternalMessageInfo
+
+func (m *BatchCreateBlogsResponse) GetResults() []*BatchCreateBlogsResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
 type ReadBlogRequest struct {
-	BlogId               string   `protobuf:"bytes,1,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
+	BlogId string `protobuf:"bytes,1,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
+	// also return the blog when it is in the trash, which only its author may
+	// do when authentication is enabled
+	ShowDeleted          bool     `protobuf:"varint,2,opt,name=show_deleted,json=showDeleted,proto3" json:"show_deleted,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -176,7 +466,7 @@ func (m *ReadBlogRequest) Reset()         { *m = ReadBlogRequest{} }
 func (m *ReadBlogRequest) String() string { return proto.CompactTextString(m) }
 func (*ReadBlogRequest) ProtoMessage()    {}
 func (*ReadBlogRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e8ca58b83c5c15c8, []int{3}
+	return fileDescriptor_e8ca58b83c5c15c8, []int{6}
 }
 
 func (m *ReadBlogRequest) XXX_Unmarshal(b []byte) error {
@@ -204,6 +494,13 @@ func (m *ReadBlogRequest) GetBlogId() string {
 	return ""
 }
 
+func (m *ReadBlogRequest) GetShowDeleted() bool {
+	if m != nil {
+		return m.ShowDeleted
+	}
+	return false
+}
+
 type ReadBlogResponse struct {
 	Blog                 *Blog    `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -215,7 +512,7 @@ func (m *ReadBlogResponse) Reset()         { *m = ReadBlogResponse{} }
 func (m *ReadBlogResponse) String() string { return proto.CompactTextString(m) }
 func (*ReadBlogResponse) ProtoMessage()    {}
 func (*ReadBlogResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e8ca58b83c5c15c8, []int{4}
+	return fileDescriptor_e8ca58b83c5c15c8, []int{7}
 }
 
 func (m *ReadBlogResponse) XXX_Unmarshal(b []byte) error {
@@ -244,7 +541,13 @@ func (m *ReadBlogResponse) GetBlog() *Blog {
 }
 
 type UpdateBlogRequest struct {
-	Blog                 *Blog    `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	Blog *Blog `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	// fields of blog to update: author_id, title and content, or "*" for all of them.
+	// every field is updated when the mask is empty
+	UpdateMask *field_mask.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// who makes this change, recorded in the revision history. The subject of the
+	// bearer token replaces it when authentication is enabled
+	Editor               string   `protobuf:"bytes,3,opt,name=editor,proto3" json:"editor,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -254,7 +557,7 @@ func (m *UpdateBlogRequest) Reset()         { *m = UpdateBlogRequest{} }
 func (m *UpdateBlogRequest) String() string { return proto.CompactTextString(m) }
 func (*UpdateBlogRequest) ProtoMessage()    {}
 func (*UpdateBlogRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e8ca58b83c5c15c8, []int{5}
+	return fileDescriptor_e8ca58b83c5c15c8, []int{8}
 }
 
 func (m *UpdateBlogRequest) XXX_Unmarshal(b []byte) error {
@@ -282,6 +585,20 @@ func (m *UpdateBlogRequest) GetBlog() *Blog {
 	return nil
 }
 
+func (m *UpdateBlogRequest) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+func (m *UpdateBlogRequest) GetEditor() string {
+	if m != nil {
+		return m.Editor
+	}
+	return ""
+}
+
 type UpdateBlogResponse struct {
 	Blog                 *Blog    `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -293,7 +610,7 @@ func (m *UpdateBlogResponse) Reset()         { *m = UpdateBlogResponse{} }
 func (m *UpdateBlogResponse) String() string { return proto.CompactTextString(m) }
 func (*UpdateBlogResponse) ProtoMessage()    {}
 func (*UpdateBlogResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e8ca58b83c5c15c8, []int{6}
+	return fileDescriptor_e8ca58b83c5c15c8, []int{9}
 }
 
 func (m *UpdateBlogResponse) XXX_Unmarshal(b []byte) error {
@@ -322,7 +639,9 @@ func (m *UpdateBlogResponse) GetBlog() *Blog {
 }
 
 type DeleteBlogRequest struct {
-	BlogId               string   `protobuf:"bytes,1,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
+	BlogId string `protobuf:"bytes,1,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
+	// when set, only delete the blog if it still has this etag
+	Etag                 string   `protobuf:"bytes,2,opt,name=etag,proto3" json:"etag,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -332,7 +651,7 @@ func (m *DeleteBlogRequest) Reset()         { *m = DeleteBlogRequest{} }
 func (m *DeleteBlogRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteBlogRequest) ProtoMessage()    {}
 func (*DeleteBlogRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e8ca58b83c5c15c8, []int{7}
+	return fileDescriptor_e8ca58b83c5c15c8, []int{10}
 }
 
 func (m *DeleteBlogRequest) XXX_Unmarshal(b []byte) error {
@@ -360,6 +679,13 @@ func (m *DeleteBlogRequest) GetBlogId() string {
 	return ""
 }
 
+func (m *DeleteBlogRequest) GetEtag() string {
+	if m != nil {
+		return m.Etag
+	}
+	return ""
+}
+
 type DeleteBlogResponse struct {
 	BlogId               string   `protobuf:"bytes,1,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -371,7 +697,7 @@ func (m *DeleteBlogResponse) Reset()         { *m = DeleteBlogResponse{} }
 func (m *DeleteBlogResponse) String() string { return proto.CompactTextString(m) }
 func (*DeleteBlogResponse) ProtoMessage()    {}
 func (*DeleteBlogResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e8ca58b83c5c15c8, []int{8}
+	return fileDescriptor_e8ca58b83c5c15c8, []int{11}
 }
 
 func (m *DeleteBlogResponse) XXX_Unmarshal(b []byte) error {
@@ -400,6 +726,19 @@ func (m *DeleteBlogResponse) GetBlogId() string {
 }
 
 type ListBlogRequest struct {
+	// maximum number of blogs to stream, the server picks a default when 0
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of a previously received response to resume the listing
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// only list blogs written by this author
+	AuthorId string `protobuf:"bytes,3,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	// only list blogs whose title starts with this prefix
+	TitlePrefix string    `protobuf:"bytes,4,opt,name=title_prefix,json=titlePrefix,proto3" json:"title_prefix,omitempty"`
+	SortOrder   SortOrder `protobuf:"varint,5,opt,name=sort_order,json=sortOrder,proto3,enum=blog.SortOrder" json:"sort_order,omitempty"`
+	// also list blogs that are in the trash, authenticated callers must set
+	// author_id to themselves
+	ShowDeleted          bool     `protobuf:"varint,6,opt,name=show_deleted,json=showDeleted,proto3" json:"show_deleted,omitempty"`
+	OrderBy              OrderBy  `protobuf:"varint,7,opt,name=order_by,json=orderBy,proto3,enum=blog.OrderBy" json:"order_by,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -409,7 +748,7 @@ func (m *ListBlogRequest) Reset()         { *m = ListBlogRequest{} }
 func (m *ListBlogRequest) String() string { return proto.CompactTextString(m) }
 func (*ListBlogRequest) ProtoMessage()    {}
 func (*ListBlogRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e8ca58b83c5c15c8, []int{9}
+	return fileDescriptor_e8ca58b83c5c15c8, []int{12}
 }
 
 func (m *ListBlogRequest) XXX_Unmarshal(b []byte) error {
@@ -430,8 +769,59 @@ func (m *ListBlogRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ListBlogRequest proto.InternalMessageInfo
 
+func (m *ListBlogRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListBlogRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *ListBlogRequest) GetAuthorId() string {
+	if m != nil {
+		return m.AuthorId
+	}
+	return ""
+}
+
+func (m *ListBlogRequest) GetTitlePrefix() string {
+	if m != nil {
+		return m.TitlePrefix
+	}
+	return ""
+}
+
+func (m *ListBlogRequest) GetSortOrder() SortOrder {
+	if m != nil {
+		return m.SortOrder
+	}
+	return SortOrder_ASCENDING
+}
+
+func (m *ListBlogRequest) GetShowDeleted() bool {
+	if m != nil {
+		return m.ShowDeleted
+	}
+	return false
+}
+
+func (m *ListBlogRequest) GetOrderBy() OrderBy {
+	if m != nil {
+		return m.OrderBy
+	}
+	return OrderBy_ORDER_BY_ID
+}
+
 type ListBlogResponse struct {
-	Blog                 *Blog    `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	Blog *Blog `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	// resumes the listing right after this blog, empty when there are no more blogs
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -441,7 +831,7 @@ func (m *ListBlogResponse) Reset()         { *m = ListBlogResponse{} }
 func (m *ListBlogResponse) String() string { return proto.CompactTextString(m) }
 func (*ListBlogResponse) ProtoMessage()    {}
 func (*ListBlogResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e8ca58b83c5c15c8, []int{10}
+	return fileDescriptor_e8ca58b83c5c15c8, []int{13}
 }
 
 func (m *ListBlogResponse) XXX_Unmarshal(b []byte) error {
@@ -469,76 +859,913 @@ func (m *ListBlogResponse) GetBlog() *Blog {
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*Blog)(nil), "blog.Blog")
-	proto.RegisterType((*CreateBlogRequest)(nil), "blog.CreateBlogRequest")
-	proto.RegisterType((*CreateBlogResponse)(nil), "blog.CreateBlogResponse")
-	proto.RegisterType((*ReadBlogRequest)(nil), "blog.ReadBlogRequest")
-	proto.RegisterType((*ReadBlogResponse)(nil), "blog.ReadBlogResponse")
-	proto.RegisterType((*UpdateBlogRequest)(nil), "blog.UpdateBlogRequest")
-	proto.RegisterType((*UpdateBlogResponse)(nil), "blog.UpdateBlogResponse")
-	proto.RegisterType((*DeleteBlogRequest)(nil), "blog.DeleteBlogRequest")
-	proto.RegisterType((*DeleteBlogResponse)(nil), "blog.DeleteBlogResponse")
-	proto.RegisterType((*ListBlogRequest)(nil), "blog.ListBlogRequest")
-	proto.RegisterType((*ListBlogResponse)(nil), "blog.ListBlogResponse")
+func (m *ListBlogResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
 }
 
-func init() { proto.RegisterFile("internal/blog/blogpb/blog.proto", fileDescriptor_e8ca58b83c5c15c8) }
+type UndeleteBlogRequest struct {
+	BlogId               string   `protobuf:"bytes,1,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
 
-var fileDescriptor_e8ca58b83c5c15c8 = []byte{
-	// 363 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0x41, 0x4f, 0xf2, 0x40,
-	0x10, 0x85, 0x7e, 0x7c, 0x50, 0x86, 0x44, 0xec, 0x44, 0xa5, 0xc1, 0x44, 0x4d, 0x4f, 0xc6, 0x28,
-	0x1a, 0xf0, 0x66, 0x3c, 0x80, 0x5e, 0x48, 0x3c, 0x61, 0xbc, 0x78, 0x21, 0x85, 0x9d, 0xe0, 0x26,
-	0x4d, 0xb7, 0xb6, 0x8b, 0x3f, 0xd4, 0x5f, 0x64, 0xba, 0xeb, 0xd2, 0xda, 0x0d, 0x11, 0x2f, 0xd0,
-	0x99, 0x79, 0x33, 0x6f, 0xe6, 0xbd, 0x2c, 0x9c, 0xf2, 0x58, 0x52, 0x1a, 0x87, 0xd1, 0xf5, 0x22,
-	0x12, 0x2b, 0xf5, 0x93, 0x2c, 0xd4, 0xdf, 0x20, 0x49, 0x85, 0x14, 0xd8, 0xc8, 0xbf, 0x83, 0x25,
-	0x34, 0x26, 0x91, 0x58, 0xe1, 0x1e, 0x38, 0x9c, 0xf9, 0xf5, 0xb3, 0xfa, 0x79, 0x7b, 0xe6, 0x70,
-	0x86, 0xc7, 0xd0, 0x0e, 0xd7, 0xf2, 0x4d, 0xa4, 0x73, 0xce, 0x7c, 0x47, 0xa5, 0x5d, 0x9d, 0x98,
-	0x32, 0x3c, 0x80, 0xff, 0x92, 0xcb, 0x88, 0xfc, 0x7f, 0xaa, 0xa0, 0x03, 0xf4, 0xa1, 0xb5, 0x14,
-	0xb1, 0xa4, 0x58, 0xfa, 0x0d, 0x95, 0x37, 0x61, 0x30, 0x02, 0xef, 0x21, 0xa5, 0x50, 0x52, 0x4e,
-	0x35, 0xa3, 0xf7, 0x35, 0x65, 0x12, 0x4f, 0x40, 0x6d, 0xa0, 0x38, 0x3b, 0x43, 0x18, 0xa8, 0xd5,
-	0x14, 0x40, 0x6f, 0x76, 0x0b, 0x58, 0x6e, 0xca, 0x12, 0x11, 0x67, 0xf4, 0x6b, 0xd7, 0x05, 0x74,
-	0x67, 0x14, 0xb2, 0x32, 0x51, 0x0f, 0x5a, 0x79, 0x69, 0xbe, 0xb9, 0xaf, 0x99, 0x87, 0x53, 0x16,
-	0x0c, 0x61, 0xbf, 0xc0, 0xee, 0x38, 0x7f, 0x04, 0xde, 0x4b, 0xc2, 0xfe, 0x7e, 0x4a, 0xb9, 0x69,
-	0x47, 0xaa, 0x4b, 0xf0, 0x1e, 0x29, 0xa2, 0x9f, 0x54, 0x5b, 0x8f, 0xb9, 0x02, 0x2c, 0xa3, 0xbf,
-	0x39, 0xb6, 0xc2, 0x3d, 0xe8, 0x3e, 0xf1, 0x4c, 0x96, 0x46, 0xe7, 0x72, 0x14, 0xa9, 0xdd, 0x76,
-	0x1c, 0x7e, 0x3a, 0xd0, 0xc9, 0xc3, 0x67, 0x4a, 0x3f, 0xf8, 0x92, 0x70, 0x0c, 0x50, 0x98, 0x86,
-	0x3d, 0x8d, 0xb7, 0xbc, 0xef, 0xfb, 0x76, 0x41, 0x13, 0x06, 0x35, 0xbc, 0x03, 0xd7, 0xb8, 0x82,
-	0x87, 0x1a, 0x57, 0x71, 0xb4, 0x7f, 0x54, 0x4d, 0x6f, 0x9a, 0xc7, 0x00, 0x85, 0xd2, 0x86, 0xdf,
-	0x32, 0xcc, 0xf0, 0xdb, 0xa6, 0xe8, 0x11, 0x85, 0x90, 0x66, 0x84, 0x65, 0x84, 0x19, 0x61, 0x6b,
-	0x1e, 0xd4, 0xf0, 0x1e, 0x5c, 0xa3, 0xa4, 0x39, 0xa1, 0x22, 0xb6, 0x39, 0xa1, 0x2a, 0x78, 0x50,
-	0xbb, 0xa9, 0x4f, 0xdc, 0xd7, 0xa6, 0x7e, 0xae, 0x8b, 0xa6, 0x7a, 0xaa, 0xa3, 0xaf, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0x94, 0xdd, 0x38, 0xb0, 0xcd, 0x03, 0x00, 0x00,
+func (m *UndeleteBlogRequest) Reset()         { *m = UndeleteBlogRequest{} }
+func (m *UndeleteBlogRequest) String() string { return proto.CompactTextString(m) }
+func (*UndeleteBlogRequest) ProtoMessage()    {}
+func (*UndeleteBlogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{14}
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConnInterface
+func (m *UndeleteBlogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UndeleteBlogRequest.Unmarshal(m, b)
+}
+func (m *UndeleteBlogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UndeleteBlogRequest.Marshal(b, m, deterministic)
+}
+func (m *UndeleteBlogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UndeleteBlogRequest.Merge(m, src)
+}
+func (m *UndeleteBlogRequest) XXX_Size() int {
+	return xxx_messageInfo_UndeleteBlogRequest.Size(m)
+}
+func (m *UndeleteBlogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UndeleteBlogRequest.DiscardUnknown(m)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
+var xxx_messageInfo_UndeleteBlogRequest proto.InternalMessageInfo
 
-// BlogServiceClient is the client API for BlogService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type BlogServiceClient interface {
-	CreateBlog(ctx context.Context, in *CreateBlogRequest, opts ...grpc.CallOption) (*CreateBlogResponse, error)
-	// return NOT_FOUND if the request is not exist
-	ReadBlog(ctx context.Context, in *ReadBlogRequest, opts ...grpc.CallOption) (*ReadBlogResponse, error)
-	// return NOT_FOUND if the request is not exist
-	UpdateBlog(ctx context.Context, in *UpdateBlogRequest, opts ...grpc.CallOption) (*UpdateBlogResponse, error)
-	// return NOT_FOUND if the request is not exist
-	DeleteBlog(ctx context.Context, in *DeleteBlogRequest, opts ...grpc.CallOption) (*DeleteBlogResponse, error)
-	ListBlog(ctx context.Context, in *ListBlogRequest, opts ...grpc.CallOption) (BlogService_ListBlogClient, error)
+func (m *UndeleteBlogRequest) GetBlogId() string {
+	if m != nil {
+		return m.BlogId
+	}
+	return ""
 }
 
-type blogServiceClient struct {
-	cc grpc.ClientConnInterface
+type UndeleteBlogResponse struct {
+	Blog                 *Blog    `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func NewBlogServiceClient(cc grpc.ClientConnInterface) BlogServiceClient {
+func (m *UndeleteBlogResponse) Reset()         { *m = UndeleteBlogResponse{} }
+func (m *UndeleteBlogResponse) String() string { return proto.CompactTextString(m) }
+func (*UndeleteBlogResponse) ProtoMessage()    {}
+func (*UndeleteBlogResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{15}
+}
+
+func (m *UndeleteBlogResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UndeleteBlogResponse.Unmarshal(m, b)
+}
+func (m *UndeleteBlogResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UndeleteBlogResponse.Marshal(b, m, deterministic)
+}
+func (m *UndeleteBlogResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UndeleteBlogResponse.Merge(m, src)
+}
+func (m *UndeleteBlogResponse) XXX_Size() int {
+	return xxx_messageInfo_UndeleteBlogResponse.Size(m)
+}
+func (m *UndeleteBlogResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UndeleteBlogResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UndeleteBlogResponse proto.InternalMessageInfo
+
+func (m *UndeleteBlogResponse) GetBlog() *Blog {
+	if m != nil {
+		return m.Blog
+	}
+	return nil
+}
+
+type ListDeletedBlogsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListDeletedBlogsRequest) Reset()         { *m = ListDeletedBlogsRequest{} }
+func (m *ListDeletedBlogsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListDeletedBlogsRequest) ProtoMessage()    {}
+func (*ListDeletedBlogsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{16}
+}
+
+func (m *ListDeletedBlogsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDeletedBlogsRequest.Unmarshal(m, b)
+}
+func (m *ListDeletedBlogsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDeletedBlogsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListDeletedBlogsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDeletedBlogsRequest.Merge(m, src)
+}
+func (m *ListDeletedBlogsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListDeletedBlogsRequest.Size(m)
+}
+func (m *ListDeletedBlogsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDeletedBlogsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDeletedBlogsRequest proto.InternalMessageInfo
+
+type ListDeletedBlogsResponse struct {
+	Blog                 *Blog    `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListDeletedBlogsResponse) Reset()         { *m = ListDeletedBlogsResponse{} }
+func (m *ListDeletedBlogsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListDeletedBlogsResponse) ProtoMessage()    {}
+func (*ListDeletedBlogsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{17}
+}
+
+func (m *ListDeletedBlogsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDeletedBlogsResponse.Unmarshal(m, b)
+}
+func (m *ListDeletedBlogsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDeletedBlogsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListDeletedBlogsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDeletedBlogsResponse.Merge(m, src)
+}
+func (m *ListDeletedBlogsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListDeletedBlogsResponse.Size(m)
+}
+func (m *ListDeletedBlogsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDeletedBlogsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDeletedBlogsResponse proto.InternalMessageInfo
+
+func (m *ListDeletedBlogsResponse) GetBlog() *Blog {
+	if m != nil {
+		return m.Blog
+	}
+	return nil
+}
+
+type SearchBlogsRequest struct {
+	// words to look for in the title and content of the blogs
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// maximum number of matches to stream, the server picks a default when 0
+	PageSize             int32    `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchBlogsRequest) Reset()         { *m = SearchBlogsRequest{} }
+func (m *SearchBlogsRequest) String() string { return proto.CompactTextString(m) }
+func (*SearchBlogsRequest) ProtoMessage()    {}
+func (*SearchBlogsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{18}
+}
+
+func (m *SearchBlogsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchBlogsRequest.Unmarshal(m, b)
+}
+func (m *SearchBlogsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchBlogsRequest.Marshal(b, m, deterministic)
+}
+func (m *SearchBlogsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchBlogsRequest.Merge(m, src)
+}
+func (m *SearchBlogsRequest) XXX_Size() int {
+	return xxx_messageInfo_SearchBlogsRequest.Size(m)
+}
+func (m *SearchBlogsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchBlogsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchBlogsRequest proto.InternalMessageInfo
+
+func (m *SearchBlogsRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
+}
+
+func (m *SearchBlogsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+type SearchBlogsResponse struct {
+	Blog *Blog `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	// relevance of the match, higher is better
+	Score float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	// title with the matched words wrapped in <em></em>
+	TitleHighlight string `protobuf:"bytes,3,opt,name=title_highlight,json=titleHighlight,proto3" json:"title_highlight,omitempty"`
+	// part of the content around the first match with the matched words wrapped in <em></em>
+	ContentSnippet       string   `protobuf:"bytes,4,opt,name=content_snippet,json=contentSnippet,proto3" json:"content_snippet,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchBlogsResponse) Reset()         { *m = SearchBlogsResponse{} }
+func (m *SearchBlogsResponse) String() string { return proto.CompactTextString(m) }
+func (*SearchBlogsResponse) ProtoMessage()    {}
+func (*SearchBlogsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{19}
+}
+
+func (m *SearchBlogsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchBlogsResponse.Unmarshal(m, b)
+}
+func (m *SearchBlogsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchBlogsResponse.Marshal(b, m, deterministic)
+}
+func (m *SearchBlogsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchBlogsResponse.Merge(m, src)
+}
+func (m *SearchBlogsResponse) XXX_Size() int {
+	return xxx_messageInfo_SearchBlogsResponse.Size(m)
+}
+func (m *SearchBlogsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchBlogsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchBlogsResponse proto.InternalMessageInfo
+
+func (m *SearchBlogsResponse) GetBlog() *Blog {
+	if m != nil {
+		return m.Blog
+	}
+	return nil
+}
+
+func (m *SearchBlogsResponse) GetScore() float64 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+func (m *SearchBlogsResponse) GetTitleHighlight() string {
+	if m != nil {
+		return m.TitleHighlight
+	}
+	return ""
+}
+
+func (m *SearchBlogsResponse) GetContentSnippet() string {
+	if m != nil {
+		return m.ContentSnippet
+	}
+	return ""
+}
+
+type WatchBlogsRequest struct {
+	// cursor of the last event received before a disconnect, to receive the events
+	// missed since then. Only new events are streamed when empty
+	Cursor               string   `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchBlogsRequest) Reset()         { *m = WatchBlogsRequest{} }
+func (m *WatchBlogsRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchBlogsRequest) ProtoMessage()    {}
+func (*WatchBlogsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{20}
+}
+
+func (m *WatchBlogsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchBlogsRequest.Unmarshal(m, b)
+}
+func (m *WatchBlogsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchBlogsRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchBlogsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchBlogsRequest.Merge(m, src)
+}
+func (m *WatchBlogsRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchBlogsRequest.Size(m)
+}
+func (m *WatchBlogsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchBlogsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchBlogsRequest proto.InternalMessageInfo
+
+func (m *WatchBlogsRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+type WatchBlogsResponse struct {
+	Type BlogEventType `protobuf:"varint,1,opt,name=type,proto3,enum=blog.BlogEventType" json:"type,omitempty"`
+	// the blog after the change
+	Blog *Blog `protobuf:"bytes,2,opt,name=blog,proto3" json:"blog,omitempty"`
+	// position of this event in the feed, to resume watching after it
+	Cursor               string               `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	EventTime            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=event_time,json=eventTime,proto3" json:"event_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *WatchBlogsResponse) Reset()         { *m = WatchBlogsResponse{} }
+func (m *WatchBlogsResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchBlogsResponse) ProtoMessage()    {}
+func (*WatchBlogsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{21}
+}
+
+func (m *WatchBlogsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchBlogsResponse.Unmarshal(m, b)
+}
+func (m *WatchBlogsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchBlogsResponse.Marshal(b, m, deterministic)
+}
+func (m *WatchBlogsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchBlogsResponse.Merge(m, src)
+}
+func (m *WatchBlogsResponse) XXX_Size() int {
+	return xxx_messageInfo_WatchBlogsResponse.Size(m)
+}
+func (m *WatchBlogsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchBlogsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchBlogsResponse proto.InternalMessageInfo
+
+func (m *WatchBlogsResponse) GetType() BlogEventType {
+	if m != nil {
+		return m.Type
+	}
+	return BlogEventType_BLOG_EVENT_TYPE_UNSPECIFIED
+}
+
+func (m *WatchBlogsResponse) GetBlog() *Blog {
+	if m != nil {
+		return m.Blog
+	}
+	return nil
+}
+
+func (m *WatchBlogsResponse) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *WatchBlogsResponse) GetEventTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.EventTime
+	}
+	return nil
+}
+
+type BlogRevision struct {
+	BlogId string `protobuf:"bytes,1,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
+	// the etag version of the blog captured by this revision
+	RevisionId int64 `protobuf:"varint,2,opt,name=revision_id,json=revisionId,proto3" json:"revision_id,omitempty"`
+	// the blog as it was at this revision
+	Blog *Blog `protobuf:"bytes,3,opt,name=blog,proto3" json:"blog,omitempty"`
+	// when an update replaced this revision with a newer one
+	ArchiveTime *timestamp.Timestamp `protobuf:"bytes,4,opt,name=archive_time,json=archiveTime,proto3" json:"archive_time,omitempty"`
+	// who made the update that replaced this revision
+	Editor               string   `protobuf:"bytes,5,opt,name=editor,proto3" json:"editor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlogRevision) Reset()         { *m = BlogRevision{} }
+func (m *BlogRevision) String() string { return proto.CompactTextString(m) }
+func (*BlogRevision) ProtoMessage()    {}
+func (*BlogRevision) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{22}
+}
+
+func (m *BlogRevision) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlogRevision.Unmarshal(m, b)
+}
+func (m *BlogRevision) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlogRevision.Marshal(b, m, deterministic)
+}
+func (m *BlogRevision) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlogRevision.Merge(m, src)
+}
+func (m *BlogRevision) XXX_Size() int {
+	return xxx_messageInfo_BlogRevision.Size(m)
+}
+func (m *BlogRevision) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlogRevision.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlogRevision proto.InternalMessageInfo
+
+func (m *BlogRevision) GetBlogId() string {
+	if m != nil {
+		return m.BlogId
+	}
+	return ""
+}
+
+func (m *BlogRevision) GetRevisionId() int64 {
+	if m != nil {
+		return m.RevisionId
+	}
+	return 0
+}
+
+func (m *BlogRevision) GetBlog() *Blog {
+	if m != nil {
+		return m.Blog
+	}
+	return nil
+}
+
+func (m *BlogRevision) GetArchiveTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.ArchiveTime
+	}
+	return nil
+}
+
+func (m *BlogRevision) GetEditor() string {
+	if m != nil {
+		return m.Editor
+	}
+	return ""
+}
+
+type ListBlogRevisionsRequest struct {
+	BlogId               string   `protobuf:"bytes,1,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListBlogRevisionsRequest) Reset()         { *m = ListBlogRevisionsRequest{} }
+func (m *ListBlogRevisionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListBlogRevisionsRequest) ProtoMessage()    {}
+func (*ListBlogRevisionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{23}
+}
+
+func (m *ListBlogRevisionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListBlogRevisionsRequest.Unmarshal(m, b)
+}
+func (m *ListBlogRevisionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListBlogRevisionsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListBlogRevisionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListBlogRevisionsRequest.Merge(m, src)
+}
+func (m *ListBlogRevisionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListBlogRevisionsRequest.Size(m)
+}
+func (m *ListBlogRevisionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListBlogRevisionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListBlogRevisionsRequest proto.InternalMessageInfo
+
+func (m *ListBlogRevisionsRequest) GetBlogId() string {
+	if m != nil {
+		return m.BlogId
+	}
+	return ""
+}
+
+type ListBlogRevisionsResponse struct {
+	Revision             *BlogRevision `protobuf:"bytes,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListBlogRevisionsResponse) Reset()         { *m = ListBlogRevisionsResponse{} }
+func (m *ListBlogRevisionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListBlogRevisionsResponse) ProtoMessage()    {}
+func (*ListBlogRevisionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{24}
+}
+
+func (m *ListBlogRevisionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListBlogRevisionsResponse.Unmarshal(m, b)
+}
+func (m *ListBlogRevisionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListBlogRevisionsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListBlogRevisionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListBlogRevisionsResponse.Merge(m, src)
+}
+func (m *ListBlogRevisionsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListBlogRevisionsResponse.Size(m)
+}
+func (m *ListBlogRevisionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListBlogRevisionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListBlogRevisionsResponse proto.InternalMessageInfo
+
+func (m *ListBlogRevisionsResponse) GetRevision() *BlogRevision {
+	if m != nil {
+		return m.Revision
+	}
+	return nil
+}
+
+type GetBlogRevisionRequest struct {
+	BlogId               string   `protobuf:"bytes,1,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
+	RevisionId           int64    `protobuf:"varint,2,opt,name=revision_id,json=revisionId,proto3" json:"revision_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBlogRevisionRequest) Reset()         { *m = GetBlogRevisionRequest{} }
+func (m *GetBlogRevisionRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBlogRevisionRequest) ProtoMessage()    {}
+func (*GetBlogRevisionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{25}
+}
+
+func (m *GetBlogRevisionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBlogRevisionRequest.Unmarshal(m, b)
+}
+func (m *GetBlogRevisionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBlogRevisionRequest.Marshal(b, m, deterministic)
+}
+func (m *GetBlogRevisionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBlogRevisionRequest.Merge(m, src)
+}
+func (m *GetBlogRevisionRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBlogRevisionRequest.Size(m)
+}
+func (m *GetBlogRevisionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBlogRevisionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBlogRevisionRequest proto.InternalMessageInfo
+
+func (m *GetBlogRevisionRequest) GetBlogId() string {
+	if m != nil {
+		return m.BlogId
+	}
+	return ""
+}
+
+func (m *GetBlogRevisionRequest) GetRevisionId() int64 {
+	if m != nil {
+		return m.RevisionId
+	}
+	return 0
+}
+
+type GetBlogRevisionResponse struct {
+	Revision             *BlogRevision `protobuf:"bytes,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *GetBlogRevisionResponse) Reset()         { *m = GetBlogRevisionResponse{} }
+func (m *GetBlogRevisionResponse) String() string { return proto.CompactTextString(m) }
+func (*GetBlogRevisionResponse) ProtoMessage()    {}
+func (*GetBlogRevisionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{26}
+}
+
+func (m *GetBlogRevisionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBlogRevisionResponse.Unmarshal(m, b)
+}
+func (m *GetBlogRevisionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBlogRevisionResponse.Marshal(b, m, deterministic)
+}
+func (m *GetBlogRevisionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBlogRevisionResponse.Merge(m, src)
+}
+func (m *GetBlogRevisionResponse) XXX_Size() int {
+	return xxx_messageInfo_GetBlogRevisionResponse.Size(m)
+}
+func (m *GetBlogRevisionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBlogRevisionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBlogRevisionResponse proto.InternalMessageInfo
+
+func (m *GetBlogRevisionResponse) GetRevision() *BlogRevision {
+	if m != nil {
+		return m.Revision
+	}
+	return nil
+}
+
+type RestoreBlogRevisionRequest struct {
+	BlogId     string `protobuf:"bytes,1,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
+	RevisionId int64  `protobuf:"varint,2,opt,name=revision_id,json=revisionId,proto3" json:"revision_id,omitempty"`
+	// who restores the revision, recorded in the revision history. The subject of the
+	// bearer token replaces it when authentication is enabled
+	Editor               string   `protobuf:"bytes,3,opt,name=editor,proto3" json:"editor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreBlogRevisionRequest) Reset()         { *m = RestoreBlogRevisionRequest{} }
+func (m *RestoreBlogRevisionRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreBlogRevisionRequest) ProtoMessage()    {}
+func (*RestoreBlogRevisionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{27}
+}
+
+func (m *RestoreBlogRevisionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreBlogRevisionRequest.Unmarshal(m, b)
+}
+func (m *RestoreBlogRevisionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreBlogRevisionRequest.Marshal(b, m, deterministic)
+}
+func (m *RestoreBlogRevisionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreBlogRevisionRequest.Merge(m, src)
+}
+func (m *RestoreBlogRevisionRequest) XXX_Size() int {
+	return xxx_messageInfo_RestoreBlogRevisionRequest.Size(m)
+}
+func (m *RestoreBlogRevisionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreBlogRevisionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreBlogRevisionRequest proto.InternalMessageInfo
+
+func (m *RestoreBlogRevisionRequest) GetBlogId() string {
+	if m != nil {
+		return m.BlogId
+	}
+	return ""
+}
+
+func (m *RestoreBlogRevisionRequest) GetRevisionId() int64 {
+	if m != nil {
+		return m.RevisionId
+	}
+	return 0
+}
+
+func (m *RestoreBlogRevisionRequest) GetEditor() string {
+	if m != nil {
+		return m.Editor
+	}
+	return ""
+}
+
+type RestoreBlogRevisionResponse struct {
+	Blog                 *Blog    `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreBlogRevisionResponse) Reset()         { *m = RestoreBlogRevisionResponse{} }
+func (m *RestoreBlogRevisionResponse) String() string { return proto.CompactTextString(m) }
+func (*RestoreBlogRevisionResponse) ProtoMessage()    {}
+func (*RestoreBlogRevisionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e8ca58b83c5c15c8, []int{28}
+}
+
+func (m *RestoreBlogRevisionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreBlogRevisionResponse.Unmarshal(m, b)
+}
+func (m *RestoreBlogRevisionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreBlogRevisionResponse.Marshal(b, m, deterministic)
+}
+func (m *RestoreBlogRevisionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreBlogRevisionResponse.Merge(m, src)
+}
+func (m *RestoreBlogRevisionResponse) XXX_Size() int {
+	return xxx_messageInfo_RestoreBlogRevisionResponse.Size(m)
+}
+func (m *RestoreBlogRevisionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreBlogRevisionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreBlogRevisionResponse proto.InternalMessageInfo
+
+func (m *RestoreBlogRevisionResponse) GetBlog() *Blog {
+	if m != nil {
+		return m.Blog
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("blog.SortOrder", SortOrder_name, SortOrder_value)
+	proto.RegisterEnum("blog.OrderBy", OrderBy_name, OrderBy_value)
+	proto.RegisterEnum("blog.BlogEventType", BlogEventType_name, BlogEventType_value)
+	proto.RegisterType((*Blog)(nil), "blog.Blog")
+	proto.RegisterType((*CreateBlogRequest)(nil), "blog.CreateBlogRequest")
+	proto.RegisterType((*CreateBlogResponse)(nil), "blog.CreateBlogResponse")
+	proto.RegisterType((*BatchCreateBlogsRequest)(nil), "blog.BatchCreateBlogsRequest")
+	proto.RegisterType((*BatchCreateBlogsResult)(nil), "blog.BatchCreateBlogsResult")
+	proto.RegisterType((*BatchCreateBlogsResponse)(nil), "blog.BatchCreateBlogsResponse")
+	proto.RegisterType((*ReadBlogRequest)(nil), "blog.ReadBlogRequest")
+	proto.RegisterType((*ReadBlogResponse)(nil), "blog.ReadBlogResponse")
+	proto.RegisterType((*UpdateBlogRequest)(nil), "blog.UpdateBlogRequest")
+	proto.RegisterType((*UpdateBlogResponse)(nil), "blog.UpdateBlogResponse")
+	proto.RegisterType((*DeleteBlogRequest)(nil), "blog.DeleteBlogRequest")
+	proto.RegisterType((*DeleteBlogResponse)(nil), "blog.DeleteBlogResponse")
+	proto.RegisterType((*ListBlogRequest)(nil), "blog.ListBlogRequest")
+	proto.RegisterType((*ListBlogResponse)(nil), "blog.ListBlogResponse")
+	proto.RegisterType((*UndeleteBlogRequest)(nil), "blog.UndeleteBlogRequest")
+	proto.RegisterType((*UndeleteBlogResponse)(nil), "blog.UndeleteBlogResponse")
+	proto.RegisterType((*ListDeletedBlogsRequest)(nil), "blog.ListDeletedBlogsRequest")
+	proto.RegisterType((*ListDeletedBlogsResponse)(nil), "blog.ListDeletedBlogsResponse")
+	proto.RegisterType((*SearchBlogsRequest)(nil), "blog.SearchBlogsRequest")
+	proto.RegisterType((*SearchBlogsResponse)(nil), "blog.SearchBlogsResponse")
+	proto.RegisterType((*WatchBlogsRequest)(nil), "blog.WatchBlogsRequest")
+	proto.RegisterType((*WatchBlogsResponse)(nil), "blog.WatchBlogsResponse")
+	proto.RegisterType((*BlogRevision)(nil), "blog.BlogRevision")
+	proto.RegisterType((*ListBlogRevisionsRequest)(nil), "blog.ListBlogRevisionsRequest")
+	proto.RegisterType((*ListBlogRevisionsResponse)(nil), "blog.ListBlogRevisionsResponse")
+	proto.RegisterType((*GetBlogRevisionRequest)(nil), "blog.GetBlogRevisionRequest")
+	proto.RegisterType((*GetBlogRevisionResponse)(nil), "blog.GetBlogRevisionResponse")
+	proto.RegisterType((*RestoreBlogRevisionRequest)(nil), "blog.RestoreBlogRevisionRequest")
+	proto.RegisterType((*RestoreBlogRevisionResponse)(nil), "blog.RestoreBlogRevisionResponse")
+}
+
+func init() { proto.RegisterFile("internal/blog/blogpb/blog.proto", fileDescriptor_e8ca58b83c5c15c8) }
+
+var fileDescriptor_e8ca58b83c5c15c8 = []byte{
+	// 1467 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xcd, 0x6e, 0xdb, 0x46,
+	0x10, 0x0e, 0x65, 0x5b, 0x96, 0x86, 0xb6, 0x2c, 0xaf, 0x1d, 0x9b, 0xa6, 0x13, 0xff, 0x10, 0x45,
+	0x63, 0xb8, 0xa8, 0x94, 0x2a, 0x45, 0x80, 0xa6, 0x08, 0xd0, 0xd8, 0x62, 0x5c, 0xa1, 0x89, 0x6c,
+	0x50, 0x72, 0xfe, 0x10, 0x80, 0xa0, 0xc5, 0x8d, 0x44, 0x44, 0x21, 0x19, 0x72, 0xe5, 0xc6, 0x29,
+	0x72, 0xc9, 0xa9, 0xe8, 0xb5, 0xe7, 0xbe, 0x42, 0xd1, 0x37, 0xe8, 0x43, 0xf4, 0x01, 0x7a, 0xe9,
+	0x83, 0x14, 0xfb, 0x27, 0x51, 0xa2, 0x54, 0xa9, 0x45, 0x2f, 0x09, 0xe7, 0x9b, 0xd9, 0xf9, 0xdf,
+	0x99, 0x95, 0x61, 0xd7, 0xf3, 0x09, 0x8e, 0x7c, 0xa7, 0x5b, 0xbe, 0xe8, 0x06, 0x6d, 0xf6, 0x4f,
+	0x78, 0xc1, 0xfe, 0x2b, 0x85, 0x51, 0x40, 0x02, 0x34, 0x4f, 0xbf, 0xf5, 0x1b, 0xed, 0x20, 0x68,
+	0x77, 0x71, 0xd9, 0x09, 0xbd, 0xb2, 0xe3, 0xfb, 0x01, 0x71, 0x88, 0x17, 0xf8, 0x31, 0x97, 0xd1,
+	0xf7, 0x04, 0x97, 0x51, 0x17, 0xbd, 0x57, 0xe5, 0x57, 0x1e, 0xee, 0xba, 0xf6, 0x1b, 0x27, 0x7e,
+	0x2d, 0x24, 0x76, 0x47, 0x25, 0x88, 0xf7, 0x06, 0xc7, 0xc4, 0x79, 0x13, 0x0a, 0x81, 0x4d, 0x21,
+	0x10, 0x85, 0xad, 0x72, 0x4c, 0x1c, 0xd2, 0x13, 0xba, 0x8d, 0x5f, 0x33, 0x30, 0x7f, 0xd4, 0x0d,
+	0xda, 0xa8, 0x00, 0x19, 0xcf, 0xd5, 0x94, 0x3d, 0xe5, 0x20, 0x6f, 0x65, 0x3c, 0x17, 0x6d, 0x43,
+	0xde, 0xe9, 0x91, 0x4e, 0x10, 0xd9, 0x9e, 0xab, 0x65, 0x18, 0x9c, 0xe3, 0x40, 0xcd, 0x45, 0xeb,
+	0xb0, 0x40, 0x3c, 0xd2, 0xc5, 0xda, 0x1c, 0x63, 0x70, 0x02, 0x69, 0xb0, 0xd8, 0x0a, 0x7c, 0x82,
+	0x7d, 0xa2, 0xcd, 0x33, 0x5c, 0x92, 0x08, 0xc1, 0x3c, 0x26, 0x4e, 0x5b, 0x5b, 0x60, 0x30, 0xfb,
+	0x46, 0x5f, 0x83, 0xea, 0xe2, 0x2e, 0x26, 0xd8, 0xa6, 0xce, 0x6a, 0xd9, 0x3d, 0xe5, 0x40, 0xad,
+	0xe8, 0x25, 0xee, 0x68, 0x49, 0x46, 0x52, 0x6a, 0xca, 0x48, 0x2c, 0xe0, 0xe2, 0x14, 0xa0, 0x87,
+	0x5b, 0x11, 0x76, 0xe4, 0xe1, 0xc5, 0xe9, 0x87, 0xb9, 0xb8, 0x3c, 0xdc, 0x0b, 0xdd, 0xfe, 0xe1,
+	0xdc, 0xf4, 0xc3, 0x5c, 0x9c, 0x02, 0xc6, 0x1d, 0x58, 0x3d, 0x66, 0xaa, 0x68, 0xd6, 0x2c, 0xfc,
+	0xb6, 0x87, 0x63, 0x82, 0x76, 0x80, 0xd5, 0x91, 0xa5, 0x4f, 0xad, 0x40, 0x89, 0x12, 0x25, 0x26,
+	0xc0, 0x70, 0xe3, 0x4b, 0x40, 0xc9, 0x43, 0x71, 0x18, 0xf8, 0x31, 0x9e, 0x7a, 0xea, 0xa3, 0x02,
+	0x9b, 0x47, 0x0e, 0x69, 0x75, 0x06, 0x67, 0xe3, 0x19, 0x2d, 0xa2, 0x4f, 0xa0, 0xe0, 0x74, 0xbb,
+	0x76, 0x10, 0xd9, 0x7e, 0x40, 0x3a, 0x9e, 0xdf, 0x66, 0x35, 0xcc, 0x59, 0x4b, 0x4e, 0xb7, 0x7b,
+	0x1a, 0xd5, 0x39, 0x86, 0xf6, 0x61, 0x29, 0x8c, 0x70, 0x8c, 0xa3, 0x4b, 0x6c, 0x7b, 0x6e, 0xcc,
+	0xca, 0x99, 0xb3, 0x54, 0x89, 0xd5, 0xdc, 0xd8, 0x78, 0x0f, 0x1b, 0x69, 0x1f, 0xe2, 0x5e, 0x97,
+	0xd0, 0x26, 0xf0, 0x7c, 0x17, 0xbf, 0x63, 0x3e, 0x2c, 0x58, 0x9c, 0xe8, 0x3b, 0x96, 0x99, 0xe0,
+	0xd8, 0x21, 0x64, 0x79, 0x03, 0x32, 0x63, 0x6a, 0x05, 0xc9, 0xbc, 0x47, 0x61, 0xab, 0xd4, 0x60,
+	0x1c, 0x4b, 0x48, 0x18, 0x16, 0x68, 0x63, 0x6c, 0xf3, 0xe4, 0xdd, 0x85, 0xc5, 0x88, 0xf9, 0x11,
+	0x6b, 0xca, 0xde, 0xdc, 0x81, 0x5a, 0xb9, 0x21, 0x4c, 0x8d, 0x75, 0xd6, 0x92, 0xc2, 0xc6, 0x63,
+	0x58, 0xb1, 0xb0, 0xe3, 0x26, 0xab, 0xb7, 0x09, 0x8b, 0xf4, 0xa8, 0xdd, 0xef, 0xff, 0x2c, 0x25,
+	0x6b, 0x2e, 0x4d, 0x4f, 0xdc, 0x09, 0xbe, 0xb7, 0x79, 0xe3, 0xb9, 0x22, 0x85, 0x2a, 0xc5, 0xaa,
+	0x1c, 0x32, 0x2a, 0x50, 0x1c, 0xa8, 0x9b, 0xb1, 0xae, 0x3f, 0x2a, 0xb0, 0x7a, 0xce, 0x3a, 0xea,
+	0x5f, 0xf4, 0x50, 0xa2, 0x6b, 0xe9, 0xc5, 0xd7, 0x32, 0x13, 0xba, 0xf6, 0x21, 0x9d, 0x0d, 0x8f,
+	0x9d, 0xf8, 0xb5, 0xec, 0x5a, 0xfa, 0x8d, 0x36, 0x20, 0x8b, 0x5d, 0x8f, 0x04, 0x91, 0xb8, 0xb1,
+	0x82, 0xa2, 0x8d, 0x99, 0xf4, 0x64, 0xc6, 0x00, 0xbe, 0x81, 0x55, 0x1e, 0xff, 0x4c, 0x59, 0x94,
+	0x97, 0x3f, 0x33, 0xb8, 0xfc, 0xc6, 0xe7, 0x80, 0x92, 0x1a, 0x84, 0xdd, 0x49, 0x2a, 0x8c, 0x9f,
+	0x32, 0xb0, 0xf2, 0xc8, 0x8b, 0x49, 0xd2, 0xde, 0x36, 0xe4, 0x43, 0xa7, 0x8d, 0xed, 0xd8, 0x7b,
+	0x8f, 0x45, 0x0b, 0xe6, 0x28, 0xd0, 0xf0, 0xde, 0x63, 0x74, 0x13, 0x80, 0x31, 0x49, 0xf0, 0x1a,
+	0xfb, 0xc2, 0x32, 0x13, 0x6f, 0x52, 0x60, 0x78, 0xb8, 0xcd, 0x8d, 0x0c, 0xb7, 0x7d, 0x58, 0x62,
+	0xf3, 0xcc, 0x0e, 0x23, 0xfc, 0xca, 0x7b, 0x27, 0x66, 0x99, 0xca, 0xb0, 0x33, 0x06, 0xa1, 0x12,
+	0x40, 0x1c, 0x44, 0xc4, 0x0e, 0x22, 0x17, 0x47, 0x6c, 0xaa, 0x15, 0x2a, 0x2b, 0x3c, 0x4d, 0x8d,
+	0x20, 0x22, 0xa7, 0x14, 0xb6, 0xf2, 0xb1, 0xfc, 0x4c, 0x35, 0x52, 0x36, 0xd5, 0x48, 0xe8, 0x00,
+	0x72, 0x4c, 0x9b, 0x7d, 0x71, 0xc5, 0xc6, 0x59, 0xa1, 0xb2, 0xcc, 0x15, 0x32, 0x0d, 0x47, 0x57,
+	0xd6, 0x62, 0xc0, 0x3f, 0x8c, 0x17, 0x50, 0x1c, 0xe4, 0x62, 0xb6, 0x8a, 0xa1, 0x4f, 0x61, 0xc5,
+	0xc7, 0xef, 0x88, 0x9d, 0x4a, 0xca, 0x32, 0x85, 0xcf, 0x64, 0x62, 0x8c, 0x12, 0xac, 0x9d, 0xfb,
+	0xee, 0xcc, 0xb5, 0x35, 0xee, 0xc2, 0xfa, 0xb0, 0xfc, 0x8c, 0x1d, 0xb4, 0x05, 0x9b, 0x34, 0x06,
+	0x11, 0x7c, 0x72, 0xb2, 0x19, 0xf7, 0x40, 0x4b, 0xb3, 0x66, 0x54, 0x7b, 0x02, 0xa8, 0x81, 0x9d,
+	0xa8, 0xd5, 0x19, 0x9a, 0x95, 0xeb, 0xb0, 0xf0, 0xb6, 0x87, 0xa3, 0x2b, 0xe1, 0x3b, 0x27, 0x86,
+	0xfb, 0x27, 0x33, 0xdc, 0x3f, 0xc6, 0x2f, 0x0a, 0xac, 0x0d, 0x69, 0x9a, 0x31, 0xcf, 0xeb, 0xb0,
+	0x10, 0xb7, 0x82, 0x88, 0x2b, 0x54, 0x2c, 0x4e, 0xa0, 0x5b, 0xb0, 0xc2, 0x3b, 0xaa, 0xe3, 0xb5,
+	0x3b, 0x5d, 0xaf, 0xdd, 0x21, 0xa2, 0xe9, 0x0a, 0x0c, 0xfe, 0x56, 0xa2, 0x54, 0x50, 0xac, 0x4c,
+	0x3b, 0xf6, 0xbd, 0x30, 0xc4, 0x72, 0x93, 0x16, 0x04, 0xdc, 0xe0, 0xa8, 0xf1, 0x19, 0xac, 0x3e,
+	0xa5, 0x83, 0x6e, 0x28, 0xce, 0x0d, 0xc8, 0xb6, 0x7a, 0x51, 0x1c, 0x44, 0xb2, 0x48, 0x9c, 0x32,
+	0x7e, 0x53, 0x00, 0x25, 0xa5, 0x45, 0x2c, 0xb7, 0x60, 0x9e, 0x5c, 0x85, 0xfc, 0xee, 0x14, 0x2a,
+	0x6b, 0x83, 0x58, 0xcc, 0x4b, 0xec, 0x93, 0xe6, 0x55, 0x88, 0x2d, 0x26, 0x30, 0x75, 0xa4, 0x0f,
+	0xec, 0xce, 0x25, 0xed, 0xa2, 0xaf, 0x00, 0x30, 0x55, 0xc5, 0xd7, 0xec, 0xfc, 0xd4, 0x35, 0x9b,
+	0x67, 0xd2, 0x6c, 0xcb, 0xfe, 0xae, 0xc0, 0x12, 0x6f, 0xa8, 0x4b, 0x2f, 0xf6, 0x02, 0x7f, 0xf2,
+	0x74, 0xd9, 0x05, 0x35, 0x12, 0x42, 0xf2, 0xa5, 0x32, 0x67, 0x81, 0x84, 0x6a, 0x6e, 0xdf, 0xfb,
+	0xb9, 0x09, 0xde, 0xdf, 0x87, 0x25, 0x5a, 0x67, 0xef, 0x12, 0xcf, 0xea, 0xa7, 0x2a, 0xe4, 0x29,
+	0x92, 0x98, 0xac, 0x0b, 0x43, 0x93, 0xf5, 0x0e, 0x6f, 0xe3, 0x64, 0x10, 0xf1, 0xd4, 0xeb, 0xf4,
+	0x1d, 0x6c, 0x8d, 0x39, 0x24, 0xea, 0x55, 0x82, 0x9c, 0x0c, 0x4b, 0xf4, 0x1f, 0x4a, 0x04, 0x23,
+	0x38, 0x56, 0x5f, 0xc6, 0xb0, 0x60, 0xe3, 0x04, 0x0f, 0xe9, 0x9a, 0x3a, 0xaa, 0xa7, 0x25, 0xd3,
+	0xa8, 0xc1, 0x66, 0x4a, 0xe7, 0x7f, 0x74, 0xcf, 0x07, 0xdd, 0xc2, 0x31, 0x09, 0x22, 0xfc, 0xbf,
+	0xba, 0x38, 0x71, 0xd5, 0xdd, 0x87, 0xed, 0xb1, 0xf6, 0x66, 0xbb, 0xd9, 0x87, 0x87, 0x90, 0xef,
+	0x8f, 0x76, 0xb4, 0x0c, 0xf9, 0x07, 0x8d, 0x63, 0xb3, 0x5e, 0xad, 0xd5, 0x4f, 0x8a, 0xd7, 0x50,
+	0x01, 0xa0, 0x6a, 0xf6, 0x69, 0xe5, 0xb0, 0x0e, 0x8b, 0x62, 0x6a, 0xa3, 0x15, 0x50, 0x4f, 0xad,
+	0xaa, 0x69, 0xd9, 0x47, 0xcf, 0xed, 0x5a, 0xb5, 0x78, 0x0d, 0x69, 0xb0, 0xde, 0x07, 0x8e, 0x2d,
+	0xf3, 0x41, 0xd3, 0xb4, 0x9b, 0xb5, 0xc7, 0x66, 0x51, 0x19, 0xe2, 0x9c, 0x9f, 0x55, 0xfb, 0x9c,
+	0xcc, 0xe1, 0x53, 0x58, 0x1e, 0xba, 0x97, 0x68, 0x17, 0xb6, 0x8f, 0x1e, 0x9d, 0x9e, 0xd8, 0xe6,
+	0x13, 0xb3, 0xde, 0xb4, 0x9b, 0xcf, 0xcf, 0x4c, 0xfb, 0xbc, 0xde, 0x38, 0x33, 0x8f, 0x6b, 0x0f,
+	0x6b, 0x26, 0xb5, 0xa2, 0xc2, 0x22, 0x57, 0x5e, 0x2d, 0x2a, 0x94, 0xe0, 0xfa, 0xaa, 0xc5, 0x0c,
+	0x25, 0xaa, 0xe6, 0x23, 0x93, 0x12, 0x73, 0x95, 0x3f, 0x73, 0xa0, 0x52, 0xcd, 0x0d, 0x1c, 0x5d,
+	0x7a, 0x2d, 0x8c, 0x9e, 0x01, 0x0c, 0x9e, 0x4e, 0x68, 0x93, 0x27, 0x21, 0xf5, 0xdc, 0xd5, 0xb5,
+	0x34, 0x83, 0x67, 0xd1, 0xd8, 0xfc, 0xf8, 0xc7, 0x5f, 0x3f, 0x67, 0x56, 0x8d, 0x7c, 0xf9, 0xf2,
+	0x0b, 0xf6, 0x33, 0x27, 0xbe, 0xc7, 0x6f, 0xd9, 0x39, 0x14, 0x47, 0x5f, 0x66, 0xe8, 0xe6, 0xa4,
+	0x17, 0x1b, 0xb7, 0xb2, 0x33, 0x89, 0x2d, 0x6c, 0x5d, 0x3b, 0x50, 0xd0, 0x13, 0xc8, 0xc9, 0xe7,
+	0x17, 0xba, 0xce, 0xe5, 0x47, 0x5e, 0x77, 0xfa, 0xc6, 0x28, 0x2c, 0x8e, 0x6f, 0x33, 0x57, 0xaf,
+	0xa3, 0xb5, 0xbe, 0xab, 0xe5, 0x1f, 0x44, 0xcb, 0x7d, 0x40, 0x17, 0x00, 0x83, 0x77, 0x91, 0x4c,
+	0x44, 0xea, 0xcd, 0xa6, 0x6b, 0x69, 0x86, 0xd0, 0xbe, 0xcf, 0xb4, 0x6f, 0x57, 0x46, 0xb5, 0x97,
+	0x3c, 0xf7, 0x83, 0x48, 0xc9, 0x4b, 0x80, 0xc1, 0x1b, 0x48, 0xda, 0x48, 0xbd, 0xab, 0x74, 0x2d,
+	0xcd, 0x18, 0x8e, 0xe0, 0x70, 0x6c, 0x04, 0x27, 0xb0, 0x94, 0xdc, 0xcc, 0x68, 0x4b, 0xb8, 0x9a,
+	0xde, 0xee, 0xba, 0x3e, 0x8e, 0x25, 0x93, 0x4c, 0x2b, 0x37, 0xba, 0x8f, 0x65, 0xe5, 0x26, 0xac,
+	0x70, 0x7d, 0x67, 0x12, 0x5b, 0x2a, 0xbd, 0xad, 0xa0, 0x3a, 0xe4, 0xe4, 0xa8, 0x93, 0x95, 0x1b,
+	0x79, 0xe1, 0xe9, 0x1b, 0xa3, 0xb0, 0x38, 0xbe, 0xca, 0xe2, 0x56, 0xd1, 0xa0, 0xc9, 0x6e, 0x2b,
+	0xe8, 0x21, 0xa8, 0x89, 0x85, 0x8d, 0x44, 0xd6, 0xd2, 0xaf, 0x01, 0x7d, 0x6b, 0x0c, 0x27, 0xe1,
+	0xd7, 0x31, 0xc0, 0x60, 0x57, 0xca, 0xaa, 0xa4, 0x76, 0xad, 0xae, 0xa5, 0x19, 0x09, 0x25, 0xcf,
+	0x60, 0x35, 0x35, 0xc7, 0xd1, 0xce, 0x68, 0x38, 0xc3, 0x5b, 0x41, 0xdf, 0x9d, 0xc8, 0x4f, 0x68,
+	0x3e, 0x83, 0x95, 0x91, 0x01, 0x8c, 0xc4, 0x0f, 0x9f, 0xf1, 0xb3, 0x5e, 0xbf, 0x39, 0x81, 0xdb,
+	0xaf, 0xef, 0x4b, 0x58, 0x1b, 0x33, 0x17, 0xd1, 0x9e, 0xbc, 0x36, 0x93, 0x46, 0xb4, 0xbe, 0xff,
+	0x0f, 0x12, 0x52, 0xfb, 0x51, 0xee, 0x45, 0x96, 0xff, 0xd1, 0xe3, 0x22, 0xcb, 0x36, 0xe9, 0x9d,
+	0xbf, 0x07, 0x00, 0x11, 0x08, 0x1e, 0x67, 0x13, 0x11, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// BlogServiceClient is the client API for BlogService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BlogServiceClient interface {
+	CreateBlog(ctx context.Context, in *CreateBlogRequest, opts ...grpc.CallOption) (*CreateBlogResponse, error)
+	// create every streamed blog and report the outcome of each one at the end
+	// return INVALID_ARGUMENT if more blogs than the server accepts in one call are sent
+	BatchCreateBlogs(ctx context.Context, opts ...grpc.CallOption) (BlogService_BatchCreateBlogsClient, error)
+	// return NOT_FOUND if the request is not exist
+	// return PERMISSION_DENIED if the blog is in the trash and the caller is not its author
+	ReadBlog(ctx context.Context, in *ReadBlogRequest, opts ...grpc.CallOption) (*ReadBlogResponse, error)
+	// return NOT_FOUND if the request is not exist
+	// return INVALID_ARGUMENT if update_mask contains an unknown path
+	// return ABORTED if blog.etag is set and does not match the stored blog
+	// return PERMISSION_DENIED if the caller is not the author of the blog
+	UpdateBlog(ctx context.Context, in *UpdateBlogRequest, opts ...grpc.CallOption) (*UpdateBlogResponse, error)
+	// move the blog to the trash, it is purged permanently after the retention period
+	// return NOT_FOUND if the request is not exist
+	// return ABORTED if etag is set and does not match the stored blog
+	// return PERMISSION_DENIED if the caller is not the author of the blog
+	DeleteBlog(ctx context.Context, in *DeleteBlogRequest, opts ...grpc.CallOption) (*DeleteBlogResponse, error)
+	// take a blog out of the trash
+	// return NOT_FOUND if the request is not exist
+	// return FAILED_PRECONDITION if the blog is not in the trash
+	// return PERMISSION_DENIED if the caller is not the author of the blog
+	UndeleteBlog(ctx context.Context, in *UndeleteBlogRequest, opts ...grpc.CallOption) (*UndeleteBlogResponse, error)
+	// stream the blogs that are in the trash, only the ones of the caller when
+	// authentication is enabled
+	ListDeletedBlogs(ctx context.Context, in *ListDeletedBlogsRequest, opts ...grpc.CallOption) (BlogService_ListDeletedBlogsClient, error)
+	// return INVALID_ARGUMENT if the page token does not match the request
+	// return PERMISSION_DENIED if show_deleted is set for the blogs of another author
+	ListBlog(ctx context.Context, in *ListBlogRequest, opts ...grpc.CallOption) (BlogService_ListBlogClient, error)
+	// stream the blogs matching a full-text query, best match first
+	// return INVALID_ARGUMENT if the query is empty
+	SearchBlogs(ctx context.Context, in *SearchBlogsRequest, opts ...grpc.CallOption) (BlogService_SearchBlogsClient, error)
+	// stream every change made to the blogs from now on, or since cursor
+	// return OUT_OF_RANGE if the events after cursor are no longer buffered
+	// return RESOURCE_EXHAUSTED if the watcher does not keep up, resume with the last cursor
+	WatchBlogs(ctx context.Context, in *WatchBlogsRequest, opts ...grpc.CallOption) (BlogService_WatchBlogsClient, error)
+	// stream the archived revisions of a blog, newest first
+	// return NOT_FOUND if the blog is not exist
+	ListBlogRevisions(ctx context.Context, in *ListBlogRevisionsRequest, opts ...grpc.CallOption) (BlogService_ListBlogRevisionsClient, error)
+	// return NOT_FOUND if the blog or the revision is not exist
+	GetBlogRevision(ctx context.Context, in *GetBlogRevisionRequest, opts ...grpc.CallOption) (*GetBlogRevisionResponse, error)
+	// update the blog with the content of an old revision, archiving the current one
+	// return NOT_FOUND if the blog or the revision is not exist
+	// return PERMISSION_DENIED if the caller is not the author of the blog
+	RestoreBlogRevision(ctx context.Context, in *RestoreBlogRevisionRequest, opts ...grpc.CallOption) (*RestoreBlogRevisionResponse, error)
+}
+
+type blogServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBlogServiceClient(cc grpc.ClientConnInterface) BlogServiceClient {
 	return &blogServiceClient{cc}
 }
 
@@ -548,7 +1775,41 @@ func (c *blogServiceClient) CreateBlog(ctx context.Context, in *CreateBlogReques
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	return out, nil
+}
+
+func (c *blogServiceClient) BatchCreateBlogs(ctx context.Context, opts ...grpc.CallOption) (BlogService_BatchCreateBlogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BlogService_serviceDesc.Streams[0], "/blog.BlogService/BatchCreateBlogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blogServiceBatchCreateBlogsClient{stream}
+	return x, nil
+}
+
+type BlogService_BatchCreateBlogsClient interface {
+	Send(*BatchCreateBlogsRequest) error
+	CloseAndRecv() (*BatchCreateBlogsResponse, error)
+	grpc.ClientStream
+}
+
+type blogServiceBatchCreateBlogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *blogServiceBatchCreateBlogsClient) Send(m *BatchCreateBlogsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *blogServiceBatchCreateBlogsClient) CloseAndRecv() (*BatchCreateBlogsResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(BatchCreateBlogsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
 
 func (c *blogServiceClient) ReadBlog(ctx context.Context, in *ReadBlogRequest, opts ...grpc.CallOption) (*ReadBlogResponse, error) {
@@ -578,8 +1839,49 @@ func (c *blogServiceClient) DeleteBlog(ctx context.Context, in *DeleteBlogReques
 	return out, nil
 }
 
+func (c *blogServiceClient) UndeleteBlog(ctx context.Context, in *UndeleteBlogRequest, opts ...grpc.CallOption) (*UndeleteBlogResponse, error) {
+	out := new(UndeleteBlogResponse)
+	err := c.cc.Invoke(ctx, "/blog.BlogService/UndeleteBlog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blogServiceClient) ListDeletedBlogs(ctx context.Context, in *ListDeletedBlogsRequest, opts ...grpc.CallOption) (BlogService_ListDeletedBlogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BlogService_serviceDesc.Streams[1], "/blog.BlogService/ListDeletedBlogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blogServiceListDeletedBlogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BlogService_ListDeletedBlogsClient interface {
+	Recv() (*ListDeletedBlogsResponse, error)
+	grpc.ClientStream
+}
+
+type blogServiceListDeletedBlogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *blogServiceListDeletedBlogsClient) Recv() (*ListDeletedBlogsResponse, error) {
+	m := new(ListDeletedBlogsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *blogServiceClient) ListBlog(ctx context.Context, in *ListBlogRequest, opts ...grpc.CallOption) (BlogService_ListBlogClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_BlogService_serviceDesc.Streams[0], "/blog.BlogService/ListBlog", opts...)
+	stream, err := c.cc.NewStream(ctx, &_BlogService_serviceDesc.Streams[2], "/blog.BlogService/ListBlog", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -610,16 +1912,166 @@ func (x *blogServiceListBlogClient) Recv() (*ListBlogResponse, error) {
 	return m, nil
 }
 
+func (c *blogServiceClient) SearchBlogs(ctx context.Context, in *SearchBlogsRequest, opts ...grpc.CallOption) (BlogService_SearchBlogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BlogService_serviceDesc.Streams[3], "/blog.BlogService/SearchBlogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blogServiceSearchBlogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BlogService_SearchBlogsClient interface {
+	Recv() (*SearchBlogsResponse, error)
+	grpc.ClientStream
+}
+
+type blogServiceSearchBlogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *blogServiceSearchBlogsClient) Recv() (*SearchBlogsResponse, error) {
+	m := new(SearchBlogsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *blogServiceClient) WatchBlogs(ctx context.Context, in *WatchBlogsRequest, opts ...grpc.CallOption) (BlogService_WatchBlogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BlogService_serviceDesc.Streams[4], "/blog.BlogService/WatchBlogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blogServiceWatchBlogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BlogService_WatchBlogsClient interface {
+	Recv() (*WatchBlogsResponse, error)
+	grpc.ClientStream
+}
+
+type blogServiceWatchBlogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *blogServiceWatchBlogsClient) Recv() (*WatchBlogsResponse, error) {
+	m := new(WatchBlogsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *blogServiceClient) ListBlogRevisions(ctx context.Context, in *ListBlogRevisionsRequest, opts ...grpc.CallOption) (BlogService_ListBlogRevisionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BlogService_serviceDesc.Streams[5], "/blog.BlogService/ListBlogRevisions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blogServiceListBlogRevisionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BlogService_ListBlogRevisionsClient interface {
+	Recv() (*ListBlogRevisionsResponse, error)
+	grpc.ClientStream
+}
+
+type blogServiceListBlogRevisionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *blogServiceListBlogRevisionsClient) Recv() (*ListBlogRevisionsResponse, error) {
+	m := new(ListBlogRevisionsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *blogServiceClient) GetBlogRevision(ctx context.Context, in *GetBlogRevisionRequest, opts ...grpc.CallOption) (*GetBlogRevisionResponse, error) {
+	out := new(GetBlogRevisionResponse)
+	err := c.cc.Invoke(ctx, "/blog.BlogService/GetBlogRevision", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blogServiceClient) RestoreBlogRevision(ctx context.Context, in *RestoreBlogRevisionRequest, opts ...grpc.CallOption) (*RestoreBlogRevisionResponse, error) {
+	out := new(RestoreBlogRevisionResponse)
+	err := c.cc.Invoke(ctx, "/blog.BlogService/RestoreBlogRevision", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BlogServiceServer is the server API for BlogService service.
 type BlogServiceServer interface {
 	CreateBlog(context.Context, *CreateBlogRequest) (*CreateBlogResponse, error)
+	// create every streamed blog and report the outcome of each one at the end
+	// return INVALID_ARGUMENT if more blogs than the server accepts in one call are sent
+	BatchCreateBlogs(BlogService_BatchCreateBlogsServer) error
 	// return NOT_FOUND if the request is not exist
+	// return PERMISSION_DENIED if the blog is in the trash and the caller is not its author
 	ReadBlog(context.Context, *ReadBlogRequest) (*ReadBlogResponse, error)
 	// return NOT_FOUND if the request is not exist
+	// return INVALID_ARGUMENT if update_mask contains an unknown path
+	// return ABORTED if blog.etag is set and does not match the stored blog
+	// return PERMISSION_DENIED if the caller is not the author of the blog
 	UpdateBlog(context.Context, *UpdateBlogRequest) (*UpdateBlogResponse, error)
+	// move the blog to the trash, it is purged permanently after the retention period
 	// return NOT_FOUND if the request is not exist
+	// return ABORTED if etag is set and does not match the stored blog
+	// return PERMISSION_DENIED if the caller is not the author of the blog
 	DeleteBlog(context.Context, *DeleteBlogRequest) (*DeleteBlogResponse, error)
+	// take a blog out of the trash
+	// return NOT_FOUND if the request is not exist
+	// return FAILED_PRECONDITION if the blog is not in the trash
+	// return PERMISSION_DENIED if the caller is not the author of the blog
+	UndeleteBlog(context.Context, *UndeleteBlogRequest) (*UndeleteBlogResponse, error)
+	// stream the blogs that are in the trash, only the ones of the caller when
+	// authentication is enabled
+	ListDeletedBlogs(*ListDeletedBlogsRequest, BlogService_ListDeletedBlogsServer) error
+	// return INVALID_ARGUMENT if the page token does not match the request
+	// return PERMISSION_DENIED if show_deleted is set for the blogs of another author
 	ListBlog(*ListBlogRequest, BlogService_ListBlogServer) error
+	// stream the blogs matching a full-text query, best match first
+	// return INVALID_ARGUMENT if the query is empty
+	SearchBlogs(*SearchBlogsRequest, BlogService_SearchBlogsServer) error
+	// stream every change made to the blogs from now on, or since cursor
+	// return OUT_OF_RANGE if the events after cursor are no longer buffered
+	// return RESOURCE_EXHAUSTED if the watcher does not keep up, resume with the last cursor
+	WatchBlogs(*WatchBlogsRequest, BlogService_WatchBlogsServer) error
+	// stream the archived revisions of a blog, newest first
+	// return NOT_FOUND if the blog is not exist
+	ListBlogRevisions(*ListBlogRevisionsRequest, BlogService_ListBlogRevisionsServer) error
+	// return NOT_FOUND if the blog or the revision is not exist
+	GetBlogRevision(context.Context, *GetBlogRevisionRequest) (*GetBlogRevisionResponse, error)
+	// update the blog with the content of an old revision, archiving the current one
+	// return NOT_FOUND if the blog or the revision is not exist
+	// return PERMISSION_DENIED if the caller is not the author of the blog
+	RestoreBlogRevision(context.Context, *RestoreBlogRevisionRequest) (*RestoreBlogRevisionResponse, error)
 }
 
 // UnimplementedBlogServiceServer can be embedded to have forward compatible implementations.
@@ -627,19 +2079,43 @@ type UnimplementedBlogServiceServer struct {
 }
 
 func (*UnimplementedBlogServiceServer) CreateBlog(ctx context.Context, req *CreateBlogRequest) (*CreateBlogResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateBlog not implemented")
+	return nil, status1.Errorf(codes.Unimplemented, "method CreateBlog not implemented")
+}
+func (*UnimplementedBlogServiceServer) BatchCreateBlogs(srv BlogService_BatchCreateBlogsServer) error {
+	return status1.Errorf(codes.Unimplemented, "method BatchCreateBlogs not implemented")
 }
 func (*UnimplementedBlogServiceServer) ReadBlog(ctx context.Context, req *ReadBlogRequest) (*ReadBlogResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ReadBlog not implemented")
+	return nil, status1.Errorf(codes.Unimplemented, "method ReadBlog not implemented")
 }
 func (*UnimplementedBlogServiceServer) UpdateBlog(ctx context.Context, req *UpdateBlogRequest) (*UpdateBlogResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateBlog not implemented")
+	return nil, status1.Errorf(codes.Unimplemented, "method UpdateBlog not implemented")
 }
 func (*UnimplementedBlogServiceServer) DeleteBlog(ctx context.Context, req *DeleteBlogRequest) (*DeleteBlogResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteBlog not implemented")
+	return nil, status1.Errorf(codes.Unimplemented, "method DeleteBlog not implemented")
+}
+func (*UnimplementedBlogServiceServer) UndeleteBlog(ctx context.Context, req *UndeleteBlogRequest) (*UndeleteBlogResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method UndeleteBlog not implemented")
+}
+func (*UnimplementedBlogServiceServer) ListDeletedBlogs(req *ListDeletedBlogsRequest, srv BlogService_ListDeletedBlogsServer) error {
+	return status1.Errorf(codes.Unimplemented, "method ListDeletedBlogs not implemented")
 }
 func (*UnimplementedBlogServiceServer) ListBlog(req *ListBlogRequest, srv BlogService_ListBlogServer) error {
-	return status.Errorf(codes.Unimplemented, "method ListBlog not implemented")
+	return status1.Errorf(codes.Unimplemented, "method ListBlog not implemented")
+}
+func (*UnimplementedBlogServiceServer) SearchBlogs(req *SearchBlogsRequest, srv BlogService_SearchBlogsServer) error {
+	return status1.Errorf(codes.Unimplemented, "method SearchBlogs not implemented")
+}
+func (*UnimplementedBlogServiceServer) WatchBlogs(req *WatchBlogsRequest, srv BlogService_WatchBlogsServer) error {
+	return status1.Errorf(codes.Unimplemented, "method WatchBlogs not implemented")
+}
+func (*UnimplementedBlogServiceServer) ListBlogRevisions(req *ListBlogRevisionsRequest, srv BlogService_ListBlogRevisionsServer) error {
+	return status1.Errorf(codes.Unimplemented, "method ListBlogRevisions not implemented")
+}
+func (*UnimplementedBlogServiceServer) GetBlogRevision(ctx context.Context, req *GetBlogRevisionRequest) (*GetBlogRevisionResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetBlogRevision not implemented")
+}
+func (*UnimplementedBlogServiceServer) RestoreBlogRevision(ctx context.Context, req *RestoreBlogRevisionRequest) (*RestoreBlogRevisionResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method RestoreBlogRevision not implemented")
 }
 
 func RegisterBlogServiceServer(s *grpc.Server, srv BlogServiceServer) {
@@ -664,6 +2140,32 @@ func _BlogService_CreateBlog_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BlogService_BatchCreateBlogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BlogServiceServer).BatchCreateBlogs(&blogServiceBatchCreateBlogsServer{stream})
+}
+
+type BlogService_BatchCreateBlogsServer interface {
+	SendAndClose(*BatchCreateBlogsResponse) error
+	Recv() (*BatchCreateBlogsRequest, error)
+	grpc.ServerStream
+}
+
+type blogServiceBatchCreateBlogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *blogServiceBatchCreateBlogsServer) SendAndClose(m *BatchCreateBlogsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *blogServiceBatchCreateBlogsServer) Recv() (*BatchCreateBlogsRequest, error) {
+	m := new(BatchCreateBlogsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _BlogService_ReadBlog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ReadBlogRequest)
 	if err := dec(in); err != nil {
@@ -718,6 +2220,45 @@ func _BlogService_DeleteBlog_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BlogService_UndeleteBlog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndeleteBlogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlogServiceServer).UndeleteBlog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blog.BlogService/UndeleteBlog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlogServiceServer).UndeleteBlog(ctx, req.(*UndeleteBlogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlogService_ListDeletedBlogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListDeletedBlogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlogServiceServer).ListDeletedBlogs(m, &blogServiceListDeletedBlogsServer{stream})
+}
+
+type BlogService_ListDeletedBlogsServer interface {
+	Send(*ListDeletedBlogsResponse) error
+	grpc.ServerStream
+}
+
+type blogServiceListDeletedBlogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *blogServiceListDeletedBlogsServer) Send(m *ListDeletedBlogsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _BlogService_ListBlog_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(ListBlogRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -739,6 +2280,105 @@ func (x *blogServiceListBlogServer) Send(m *ListBlogResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _BlogService_SearchBlogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchBlogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlogServiceServer).SearchBlogs(m, &blogServiceSearchBlogsServer{stream})
+}
+
+type BlogService_SearchBlogsServer interface {
+	Send(*SearchBlogsResponse) error
+	grpc.ServerStream
+}
+
+type blogServiceSearchBlogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *blogServiceSearchBlogsServer) Send(m *SearchBlogsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BlogService_WatchBlogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchBlogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlogServiceServer).WatchBlogs(m, &blogServiceWatchBlogsServer{stream})
+}
+
+type BlogService_WatchBlogsServer interface {
+	Send(*WatchBlogsResponse) error
+	grpc.ServerStream
+}
+
+type blogServiceWatchBlogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *blogServiceWatchBlogsServer) Send(m *WatchBlogsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BlogService_ListBlogRevisions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListBlogRevisionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlogServiceServer).ListBlogRevisions(m, &blogServiceListBlogRevisionsServer{stream})
+}
+
+type BlogService_ListBlogRevisionsServer interface {
+	Send(*ListBlogRevisionsResponse) error
+	grpc.ServerStream
+}
+
+type blogServiceListBlogRevisionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *blogServiceListBlogRevisionsServer) Send(m *ListBlogRevisionsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BlogService_GetBlogRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlogRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlogServiceServer).GetBlogRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blog.BlogService/GetBlogRevision",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlogServiceServer).GetBlogRevision(ctx, req.(*GetBlogRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlogService_RestoreBlogRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreBlogRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlogServiceServer).RestoreBlogRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blog.BlogService/RestoreBlogRevision",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlogServiceServer).RestoreBlogRevision(ctx, req.(*RestoreBlogRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _BlogService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "blog.BlogService",
 	HandlerType: (*BlogServiceServer)(nil),
@@ -759,13 +2399,50 @@ var _BlogService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteBlog",
 			Handler:    _BlogService_DeleteBlog_Handler,
 		},
+		{
+			MethodName: "UndeleteBlog",
+			Handler:    _BlogService_UndeleteBlog_Handler,
+		},
+		{
+			MethodName: "GetBlogRevision",
+			Handler:    _BlogService_GetBlogRevision_Handler,
+		},
+		{
+			MethodName: "RestoreBlogRevision",
+			Handler:    _BlogService_RestoreBlogRevision_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BatchCreateBlogs",
+			Handler:       _BlogService_BatchCreateBlogs_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ListDeletedBlogs",
+			Handler:       _BlogService_ListDeletedBlogs_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "ListBlog",
 			Handler:       _BlogService_ListBlog_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SearchBlogs",
+			Handler:       _BlogService_SearchBlogs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchBlogs",
+			Handler:       _BlogService_WatchBlogs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListBlogRevisions",
+			Handler:       _BlogService_ListBlogRevisions_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "internal/blog/blogpb/blog.proto",
 }
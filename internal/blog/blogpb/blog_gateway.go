@@ -0,0 +1,85 @@
+package blogpb
+
+import (
+	"context"
+	"github.com/golang/protobuf/proto"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/status"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ndjsonContentType is what clients accept to get ListBlog as newline delimited JSON
+const ndjsonContentType = "application/x-ndjson"
+
+func init() {
+	// the gateway's default wraps every streamed message in a {"result": ...} object,
+	// which web clients cannot parse as a whole
+	forward_BlogService_ListBlog_0 = forwardListBlog
+}
+
+// forwardListBlog writes the ListBlog stream as a JSON array of ListBlogResponse, or
+// as one ListBlogResponse per line when the client accepts application/x-ndjson.
+// An error after the first message is appended as a final {"error": status} element.
+func forwardListBlog(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, req *http.Request, recv func() (proto.Message, error), opts ...func(context.Context, http.ResponseWriter, proto.Message) error) {
+	ndjson := acceptsNDJSON(req)
+
+	// wait for the first message so errors like an invalid page token still get their HTTP status
+	msg, err := recv()
+	if err != nil && err != io.EOF {
+		runtime.HTTPError(ctx, mux, marshaler, w, req, err)
+		return
+	}
+	if ndjson {
+		w.Header().Set("Content-Type", ndjsonContentType)
+	} else {
+		w.Header().Set("Content-Type", marshaler.ContentType())
+		w.Write([]byte("["))
+	}
+	f, _ := w.(http.Flusher)
+
+	for n := 0; err != io.EOF; n++ {
+		var chunk interface{} = msg
+		if err != nil {
+			chunk = map[string]proto.Message{"error": status.Convert(err).Proto()}
+		}
+		buf, merr := marshaler.Marshal(chunk)
+		if merr != nil {
+			grpclog.Infof("Failed to marshal ListBlog response: %v", merr)
+			return
+		}
+		switch {
+		case ndjson:
+			buf = append(buf, '\n')
+		case n > 0:
+			buf = append([]byte(","), buf...)
+		}
+		if _, werr := w.Write(buf); werr != nil {
+			grpclog.Infof("Failed to send ListBlog response: %v", werr)
+			return
+		}
+		if f != nil {
+			f.Flush()
+		}
+		if err != nil {
+			break
+		}
+		msg, err = recv()
+	}
+	if !ndjson {
+		w.Write([]byte("]"))
+	}
+}
+
+// acceptsNDJSON reports whether the Accept header of req asks for application/x-ndjson
+func acceptsNDJSON(req *http.Request) bool {
+	for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept)); err == nil && mediaType == ndjsonContentType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package blogpb
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/golang/protobuf/proto"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestForwardListBlog(t *testing.T) {
+	first := &ListBlogResponse{Blog: &Blog{Id: "1", Title: "first"}}
+	second := &ListBlogResponse{Blog: &Blog{Id: "2", Title: "second"}, NextPageToken: "next"}
+	broken := status.Error(codes.Unavailable, "store is down")
+
+	tests := []struct {
+		name   string
+		accept string
+		msgs   []proto.Message
+		// err ends the stream after msgs, io.EOF when nil
+		err             error
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{"JSON array", "", []proto.Message{first, second}, nil, http.StatusOK, "application/json",
+			`[{"blog":{"id":"1","title":"first"}},{"blog":{"id":"2","title":"second"},"next_page_token":"next"}]`},
+		{"empty JSON array", "application/json", nil, nil, http.StatusOK, "application/json", `[]`},
+		{"NDJSON", "application/x-ndjson", []proto.Message{first, second}, nil, http.StatusOK, ndjsonContentType,
+			"{\"blog\":{\"id\":\"1\",\"title\":\"first\"}}\n{\"blog\":{\"id\":\"2\",\"title\":\"second\"},\"next_page_token\":\"next\"}\n"},
+		{"NDJSON among other types", "text/html, application/x-ndjson; q=0.9", []proto.Message{first}, nil, http.StatusOK, ndjsonContentType,
+			"{\"blog\":{\"id\":\"1\",\"title\":\"first\"}}\n"},
+		{"empty NDJSON", "application/x-ndjson", nil, nil, http.StatusOK, ndjsonContentType, ""},
+		{"error before the first message", "", nil, status.Error(codes.InvalidArgument, "invalid page token"), http.StatusBadRequest, "application/json", ""},
+		{"JSON array error after the first message", "", []proto.Message{first}, broken, http.StatusOK, "application/json",
+			`[{"blog":{"id":"1","title":"first"}},{"error":{"code":14,"message":"store is down"}}]`},
+		{"NDJSON error after the first message", "application/x-ndjson", []proto.Message{first}, broken, http.StatusOK, ndjsonContentType,
+			"{\"blog\":{\"id\":\"1\",\"title\":\"first\"}}\n{\"error\":{\"code\":14,\"message\":\"store is down\"}}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := tt.msgs
+			recv := func() (proto.Message, error) {
+				if len(msgs) == 0 {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return nil, io.EOF
+				}
+				msg := msgs[0]
+				msgs = msgs[1:]
+				return msg, nil
+			}
+			req := httptest.NewRequest(http.MethodGet, "/v1/blogs", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			// the marshaler the gateway picks for the request
+			mux := runtime.NewServeMux()
+			_, marshaler := runtime.MarshalerForRequest(mux, req)
+			forwardListBlog(context.Background(), mux, marshaler, rec, req, recv)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if got := rec.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %s, want %s", got, tt.wantBody)
+			}
+			// every line of NDJSON and the whole JSON array must parse
+			docs := []string{rec.Body.String()}
+			if tt.wantContentType == ndjsonContentType {
+				docs = strings.FieldsFunc(rec.Body.String(), func(r rune) bool { return r == '\n' })
+			}
+			for _, doc := range docs {
+				if !json.Valid([]byte(doc)) {
+					t.Errorf("invalid JSON %s", doc)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package blogserver
+
+import (
+	"errors"
+	"fmt"
+	"github.com/naraycitra/grpc-go-adventure/internal/blog/blogpb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// subscriberBuffer is how many events a watcher may lag behind before it is dropped
+	subscriberBuffer = 64
+	// versionWindow is how many of the latest changed blogs the bus remembers the
+	// published version of. An event can only be overtaken by the events published
+	// between the return of its store call and its own publication.
+	versionWindow = 4096
+)
+
+var (
+	// errCursorExpired is returned when a cursor points before the replay buffer
+	// or was issued by another run of the server
+	errCursorExpired = errors.New("cursor is too old to resume from")
+	errInvalidCursor = errors.New("invalid cursor")
+	// errBusClosed is returned to the watchers of a server that is stopping
+	errBusClosed = errors.New("the server is stopping")
+)
+
+// blogEvent is a change of a blog published by the mutating handlers
+type blogEvent struct {
+	Seq  uint64
+	Type blogpb.BlogEventType
+	Blog blogItem
+	Time time.Time
+}
+
+// subscriber receives the events published after it subscribed. Its channel is
+// closed when it falls more than subscriberBuffer events behind or when the bus
+// is closed, which sets closed first.
+type subscriber struct {
+	ch     chan blogEvent
+	closed bool
+}
+
+// eventBus fans blog events out to the watchers and keeps the latest ones in a
+// bounded buffer so reconnecting watchers can catch up
+type eventBus struct {
+	mu sync.Mutex
+	// epoch tells apart the cursors of different runs of the server
+	epoch string
+	seq   uint64
+	// replay holds the latest events, oldest first
+	replay      []blogEvent
+	replaySize  int
+	subscribers map[*subscriber]bool
+	closed      bool
+	// published holds the last published version of the blogs in versions, the
+	// oldest entries of versions are forgotten first
+	published map[primitive.ObjectID]publishedVersion
+	versions  []publishedKey
+}
+
+// publishedVersion orders the changes of a blog. The create time tells apart a
+// blog recreated with the id of a purged one.
+type publishedVersion struct {
+	createTime time.Time
+	version    int64
+	seq        uint64
+}
+
+type publishedKey struct {
+	id  primitive.ObjectID
+	seq uint64
+}
+
+func newEventBus(replaySize int) *eventBus {
+	return &eventBus{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		replaySize:  replaySize,
+		subscribers: make(map[*subscriber]bool),
+		published:   make(map[primitive.ObjectID]publishedVersion),
+	}
+}
+
+// publish records an event for the blog and sends it to every subscriber. The
+// handlers publish after their store call returns, so concurrent changes of a
+// blog may be published in another order than their versions: the event is
+// dropped when a newer version of the blog was already published.
+func (b *eventBus) publish(eventType blogpb.BlogEventType, item *blogItem) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.newer(item) {
+		return
+	}
+	b.seq++
+	b.published[item.ID] = publishedVersion{createTime: item.CreateTime, version: item.Version, seq: b.seq}
+	b.versions = append(b.versions, publishedKey{id: item.ID, seq: b.seq})
+	if len(b.versions) > versionWindow {
+		if oldest := b.versions[0]; b.published[oldest.id].seq == oldest.seq {
+			delete(b.published, oldest.id)
+		}
+		b.versions = b.versions[1:]
+	}
+	ev := blogEvent{Seq: b.seq, Type: eventType, Blog: *item, Time: time.Now().UTC()}
+
+	if len(b.replay) == b.replaySize && b.replaySize > 0 {
+		copy(b.replay, b.replay[1:])
+		b.replay = b.replay[:len(b.replay)-1]
+	}
+	if b.replaySize > 0 {
+		b.replay = append(b.replay, ev)
+	}
+
+	for sub := range b.subscribers {
+		select {
+		case sub.ch <- ev:
+		default:
+			// never block the handlers on a slow watcher
+			delete(b.subscribers, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// newer reports whether item is newer than the last published version of the blog
+func (b *eventBus) newer(item *blogItem) bool {
+	last, ok := b.published[item.ID]
+	if !ok || item.CreateTime.After(last.createTime) {
+		return true
+	}
+	return item.CreateTime.Equal(last.createTime) && item.Version > last.version
+}
+
+// subscribe registers a new subscriber and returns the buffered events published after
+// cursor, which the subscriber will not receive again. An empty cursor replays nothing.
+func (b *eventBus) subscribe(cursor string) ([]blogEvent, *subscriber, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, nil, errBusClosed
+	}
+	var replay []blogEvent
+	if cursor != "" {
+		after, err := b.parseCursor(cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+		// the event right after the cursor must still be buffered
+		if after < b.seq && (len(b.replay) == 0 || b.replay[0].Seq > after+1) {
+			return nil, nil, errCursorExpired
+		}
+		for _, ev := range b.replay {
+			if ev.Seq > after {
+				replay = append(replay, ev)
+			}
+		}
+	}
+
+	sub := &subscriber{ch: make(chan blogEvent, subscriberBuffer)}
+	b.subscribers[sub] = true
+	return replay, sub, nil
+}
+
+func (b *eventBus) unsubscribe(sub *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subscribers[sub] {
+		delete(b.subscribers, sub)
+		close(sub.ch)
+	}
+}
+
+// close ends every subscription and refuses the new ones
+func (b *eventBus) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		delete(b.subscribers, sub)
+		sub.closed = true
+		close(sub.ch)
+	}
+}
+
+// cursor returns the opaque position of the event in the feed
+func (b *eventBus) cursor(ev blogEvent) string {
+	return fmt.Sprintf("%s-%d", b.epoch, ev.Seq)
+}
+
+func (b *eventBus) parseCursor(cursor string) (uint64, error) {
+	i := strings.LastIndex(cursor, "-")
+	if i < 0 {
+		return 0, errInvalidCursor
+	}
+	seq, err := strconv.ParseUint(cursor[i+1:], 10, 64)
+	if err != nil {
+		return 0, errInvalidCursor
+	}
+	if cursor[:i] != b.epoch || seq > b.seq {
+		return 0, errCursorExpired
+	}
+	return seq, nil
+}
@@ -0,0 +1,246 @@
+package blogserver
+
+import (
+	"context"
+	"github.com/naraycitra/grpc-go-adventure/internal/blog/blogpb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+// receive returns the events sub has buffered
+func receive(sub *subscriber) []blogEvent {
+	var events []blogEvent
+	for {
+		select {
+		case ev, ok := <-sub.ch:
+			if !ok {
+				return events
+			}
+			events = append(events, ev)
+		default:
+			return events
+		}
+	}
+}
+
+func versions(events []blogEvent) []int64 {
+	var v []int64
+	for _, ev := range events {
+		v = append(v, ev.Blog.Version)
+	}
+	return v
+}
+
+func equalVersions(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEventBusPublishesToSubscribers(t *testing.T) {
+	b := newEventBus(16)
+	before := &blogItem{ID: primitive.NewObjectID(), Version: 1}
+	b.publish(blogpb.BlogEventType_CREATED, before)
+
+	replay, sub, err := b.subscribe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replay) != 0 {
+		t.Errorf("subscribe() without cursor replayed %d events, want none", len(replay))
+	}
+	item := &blogItem{ID: primitive.NewObjectID(), Version: 1}
+	b.publish(blogpb.BlogEventType_CREATED, item)
+	b.publish(blogpb.BlogEventType_UPDATED, &blogItem{ID: item.ID, Version: 2})
+
+	events := receive(sub)
+	if got := versions(events); !equalVersions(got, []int64{1, 2}) {
+		t.Fatalf("received versions %v, want [1 2]", got)
+	}
+	if events[0].Type != blogpb.BlogEventType_CREATED || events[1].Type != blogpb.BlogEventType_UPDATED {
+		t.Errorf("received types %v and %v, want CREATED and UPDATED", events[0].Type, events[1].Type)
+	}
+
+	b.unsubscribe(sub)
+	b.publish(blogpb.BlogEventType_UPDATED, &blogItem{ID: item.ID, Version: 3})
+	if _, ok := <-sub.ch; ok {
+		t.Error("unsubscribed subscriber received an event")
+	}
+}
+
+func TestEventBusDropsOvertakenEvents(t *testing.T) {
+	created := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	id := primitive.NewObjectID()
+	item := func(createTime time.Time, version int64) *blogItem {
+		return &blogItem{ID: id, CreateTime: createTime, Version: version}
+	}
+
+	tests := []struct {
+		name  string
+		items []*blogItem
+		want  []int64
+	}{
+		{"in order", []*blogItem{item(created, 1), item(created, 2), item(created, 3)}, []int64{1, 2, 3}},
+		{"update overtaken by a newer one", []*blogItem{item(created, 1), item(created, 3), item(created, 2)}, []int64{1, 3}},
+		{"create overtaken by an update", []*blogItem{item(created, 2), item(created, 1)}, []int64{2}},
+		{"same version twice", []*blogItem{item(created, 1), item(created, 1)}, []int64{1}},
+		{"blog recreated after a purge", []*blogItem{item(created, 4), item(created.Add(time.Hour), 1)}, []int64{4, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newEventBus(16)
+			_, sub, err := b.subscribe("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, item := range tt.items {
+				b.publish(blogpb.BlogEventType_UPDATED, item)
+			}
+			if got := versions(receive(sub)); !equalVersions(got, tt.want) {
+				t.Errorf("received versions %v, want %v", got, tt.want)
+			}
+			if got := versions(b.replay); !equalVersions(got, tt.want) {
+				t.Errorf("replay buffer holds versions %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventBusForgetsOldVersions(t *testing.T) {
+	b := newEventBus(0)
+	for i := 0; i < versionWindow+10; i++ {
+		b.publish(blogpb.BlogEventType_CREATED, &blogItem{ID: primitive.NewObjectID(), Version: 1})
+	}
+	if len(b.published) != versionWindow || len(b.versions) != versionWindow {
+		t.Errorf("bus remembers %d versions in a queue of %d, want %d", len(b.published), len(b.versions), versionWindow)
+	}
+}
+
+func TestEventBusReplaysAfterCursor(t *testing.T) {
+	b := newEventBus(3)
+	id := primitive.NewObjectID()
+	var cursors []string
+	for v := int64(1); v <= 5; v++ {
+		b.publish(blogpb.BlogEventType_UPDATED, &blogItem{ID: id, Version: v})
+		cursors = append(cursors, b.cursor(b.replay[len(b.replay)-1]))
+	}
+	otherRun := newEventBus(3)
+	otherRun.epoch = "other"
+	otherRun.publish(blogpb.BlogEventType_CREATED, &blogItem{ID: id, Version: 1})
+
+	tests := []struct {
+		name    string
+		cursor  string
+		want    []int64
+		wantErr error
+	}{
+		{"latest event", cursors[4], nil, nil},
+		{"every buffered event", cursors[1], []int64{3, 4, 5}, nil},
+		{"latest buffered events", cursors[2], []int64{4, 5}, nil},
+		{"event before the buffer", cursors[0], nil, errCursorExpired},
+		{"cursor of another run", otherRun.cursor(otherRun.replay[0]), nil, errCursorExpired},
+		{"cursor ahead of the feed", b.epoch + "-9", nil, errCursorExpired},
+		{"malformed cursor", "nope", nil, errInvalidCursor},
+		{"malformed sequence", b.epoch + "-x", nil, errInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replay, sub, err := b.subscribe(tt.cursor)
+			if err != tt.wantErr {
+				t.Fatalf("subscribe(%q) error = %v, want %v", tt.cursor, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer b.unsubscribe(sub)
+			if got := versions(replay); !equalVersions(got, tt.want) {
+				t.Errorf("subscribe(%q) replayed versions %v, want %v", tt.cursor, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventBusDropsSlowSubscribers(t *testing.T) {
+	b := newEventBus(0)
+	_, sub, err := b.subscribe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i <= subscriberBuffer; i++ {
+		b.publish(blogpb.BlogEventType_CREATED, &blogItem{ID: primitive.NewObjectID(), Version: 1})
+	}
+	if got := len(receive(sub)); got != subscriberBuffer {
+		t.Errorf("slow subscriber received %d events, want %d", got, subscriberBuffer)
+	}
+	if _, ok := <-sub.ch; ok || sub.closed {
+		t.Error("slow subscriber was not dropped as lagging behind")
+	}
+}
+
+func TestEventBusClose(t *testing.T) {
+	b := newEventBus(0)
+	_, sub, err := b.subscribe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.close()
+	if _, ok := <-sub.ch; ok || !sub.closed {
+		t.Error("close() did not end the subscription as closed")
+	}
+	if _, _, err := b.subscribe(""); err != errBusClosed {
+		t.Errorf("subscribe() after close() error = %v, want %v", err, errBusClosed)
+	}
+}
+
+func TestWatchBlogsResumesFromCursor(t *testing.T) {
+	srv := newTestServer()
+	client := serveTest(t, srv)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watch, err := client.WatchBlogs(ctx, &blogpb.WatchBlogsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// only the blogs created once the watcher subscribed are sent to it
+	for subscribed := false; !subscribed; time.Sleep(time.Millisecond) {
+		srv.events.mu.Lock()
+		subscribed = len(srv.events.subscribers) > 0
+		srv.events.mu.Unlock()
+	}
+	first := createTestBlog(t, client, context.Background(), "first")
+	ev, err := watch.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.GetType() != blogpb.BlogEventType_CREATED || ev.GetBlog().GetId() != first.GetId() {
+		t.Fatalf("first event = %v, want the creation of %s", ev, first.GetId())
+	}
+	cancel()
+
+	second := createTestBlog(t, client, context.Background(), "second")
+	watch, err = client.WatchBlogs(context.Background(), &blogpb.WatchBlogsRequest{Cursor: ev.GetCursor()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev, err = watch.Recv(); err != nil || ev.GetBlog().GetId() != second.GetId() {
+		t.Errorf("resumed event = %v, %v, want the creation of %s", ev, err, second.GetId())
+	}
+
+	watch, err = client.WatchBlogs(context.Background(), &blogpb.WatchBlogsRequest{Cursor: "nope"})
+	if err == nil {
+		_, err = watch.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("WatchBlogs() with a malformed cursor error = %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,271 @@
+package blogserver
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"sort"
+	"sync"
+	"time"
+)
+
+// memoryStore is a BlogStore that keeps every blog in process memory.
+// It is safe for concurrent use and is meant for tests and local development.
+type memoryStore struct {
+	mu    sync.RWMutex
+	blogs map[primitive.ObjectID]blogItem
+	// revisions of every blog, oldest first
+	revisions map[primitive.ObjectID][]revisionItem
+	// index is the full-text index over the title and content of blogs
+	index *invertedIndex
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		blogs:     make(map[primitive.ObjectID]blogItem),
+		revisions: make(map[primitive.ObjectID][]revisionItem),
+		index:     newInvertedIndex(),
+	}
+}
+
+func (s *memoryStore) Create(ctx context.Context, item *blogItem) (*blogItem, error) {
+	data := *item
+	if data.ID.IsZero() {
+		data.ID = primitive.NewObjectID()
+	}
+	data.Version = 1
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.blogs[data.ID]; ok {
+		return nil, errAlreadyExists
+	}
+	s.blogs[data.ID] = data
+	s.index.add(&data)
+	return &data, nil
+}
+
+func (s *memoryStore) CreateMany(ctx context.Context, items []*blogItem) ([]*blogItem, error) {
+	created := make([]*blogItem, len(items))
+	ids := make(map[primitive.ObjectID]bool, len(items))
+	for i, item := range items {
+		data := *item
+		if data.ID.IsZero() {
+			data.ID = primitive.NewObjectID()
+		}
+		data.Version = 1
+		if ids[data.ID] {
+			return nil, errAlreadyExists
+		}
+		ids[data.ID] = true
+		created[i] = &data
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, data := range created {
+		if _, ok := s.blogs[data.ID]; ok {
+			return nil, errAlreadyExists
+		}
+	}
+	for _, data := range created {
+		s.blogs[data.ID] = *data
+		s.index.add(data)
+	}
+	return created, nil
+}
+
+func (s *memoryStore) Read(ctx context.Context, id string) (*blogItem, error) {
+	oid, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	data, ok := s.blogs[oid]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, errNotFound
+	}
+	return &data, nil
+}
+
+func (s *memoryStore) Update(ctx context.Context, item *blogItem, editor string) (*blogItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.blogs[item.ID]
+	if !ok || stored.deleted() {
+		return nil, errNotFound
+	}
+	if stored.Version != item.Version {
+		return nil, errConflict
+	}
+	data := *item
+	data.Version++
+	s.blogs[data.ID] = data
+	s.index.add(&data)
+	s.revisions[data.ID] = append(s.revisions[data.ID], newRevisionItem(stored, editor))
+	return &data, nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, id string, version int64) (*blogItem, error) {
+	oid, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.blogs[oid]
+	if !ok || stored.deleted() {
+		return nil, errNotFound
+	}
+	if version != anyVersion && stored.Version != version {
+		return nil, errConflict
+	}
+	now := serverTime()
+	stored.DeleteTime = &now
+	stored.Version++
+	s.blogs[oid] = stored
+	return &stored, nil
+}
+
+func (s *memoryStore) Undelete(ctx context.Context, id string) (*blogItem, error) {
+	oid, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.blogs[oid]
+	if !ok {
+		return nil, errNotFound
+	}
+	if !stored.deleted() {
+		return nil, errNotDeleted
+	}
+	stored.DeleteTime = nil
+	stored.Version++
+	s.blogs[oid] = stored
+	return &stored, nil
+}
+
+func (s *memoryStore) Purge(ctx context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := 0
+	for oid, data := range s.blogs {
+		if data.deleted() && data.DeleteTime.Before(before) {
+			delete(s.blogs, oid)
+			delete(s.revisions, oid)
+			s.index.remove(oid)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (s *memoryStore) List(ctx context.Context, opts listOptions, fn func(*blogItem) error) error {
+	// take a snapshot so fn can call back into the store without deadlocking
+	s.mu.RLock()
+	items := make([]blogItem, 0, len(s.blogs))
+	for _, data := range s.blogs {
+		if opts.match(&data) {
+			items = append(items, data)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(items, func(i, j int) bool {
+		return opts.less(&items[i], &items[j])
+	})
+	if opts.Limit > 0 && len(items) > opts.Limit {
+		items = items[:opts.Limit]
+	}
+
+	for i := range items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(&items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) Search(ctx context.Context, query string, limit int, fn func(*searchHit) error) error {
+	s.mu.RLock()
+	ids, scores := s.index.search(queryTerms(query))
+	hits := make([]searchHit, 0, limit)
+	for _, oid := range ids {
+		if len(hits) == limit {
+			break
+		}
+		if data := s.blogs[oid]; !data.deleted() {
+			hits = append(hits, searchHit{Blog: data, Score: scores[oid]})
+		}
+	}
+	s.mu.RUnlock()
+
+	for i := range hits {
+		if err := fn(&hits[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) ListRevisions(ctx context.Context, id string, fn func(*revisionItem) error) error {
+	oid, err := parseID(id)
+	if err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	_, ok := s.blogs[oid]
+	revisions := append([]revisionItem(nil), s.revisions[oid]...)
+	s.mu.RUnlock()
+
+	if !ok {
+		return errNotFound
+	}
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if err := fn(&revisions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) ReadRevision(ctx context.Context, id string, version int64) (*revisionItem, error) {
+	oid, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.blogs[oid]; !ok {
+		return nil, errNotFound
+	}
+	for _, rev := range s.revisions[oid] {
+		if rev.Blog.Version == version {
+			return &rev, nil
+		}
+	}
+	return nil, errRevisionNotFound
+}
+
+// Ping never fails, the blogs are always at hand
+func (s *memoryStore) Ping(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,146 @@
+package blogserver
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreUpdateChecksVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     int64
+		trash       bool
+		wantErr     error
+		wantVersion int64
+	}{
+		{"current version", 1, false, nil, 2},
+		{"stale version", 0, false, errConflict, 1},
+		{"future version", 2, false, errConflict, 1},
+		{"trashed blog", 2, true, errNotFound, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newMemoryStore()
+			created, err := s.Create(ctx, &blogItem{AuthorID: "alice", Title: "old"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.trash {
+				if _, err := s.Delete(ctx, created.ID.Hex(), anyVersion); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			item := *created
+			item.Title = "new"
+			item.Version = tt.version
+			updated, err := s.Update(ctx, &item, "alice")
+			if err != tt.wantErr {
+				t.Fatalf("Update() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (updated.Version != tt.wantVersion || updated.Title != "new") {
+				t.Errorf("Update() = %+v, want version %d and the new title", updated, tt.wantVersion)
+			}
+
+			stored, err := s.Read(ctx, created.ID.Hex())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.Version != tt.wantVersion {
+				t.Errorf("stored version = %d, want %d", stored.Version, tt.wantVersion)
+			}
+			wantTitle := "old"
+			if tt.wantErr == nil {
+				wantTitle = "new"
+			}
+			if stored.Title != wantTitle {
+				t.Errorf("stored title = %q, want %q", stored.Title, wantTitle)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreDeleteChecksVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int64
+		wantErr error
+	}{
+		{"any version", anyVersion, nil},
+		{"current version", 1, nil},
+		{"stale version", 0, errConflict},
+		{"future version", 2, errConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newMemoryStore()
+			created, err := s.Create(ctx, &blogItem{AuthorID: "alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			deleted, err := s.Delete(ctx, created.ID.Hex(), tt.version)
+			if err != tt.wantErr {
+				t.Fatalf("Delete() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if deleted.DeleteTime == nil || deleted.Version != 2 {
+				t.Errorf("Delete() = %+v, want a trashed blog at version 2", deleted)
+			}
+			// the trashed blog cannot be trashed again
+			if _, err := s.Delete(ctx, created.ID.Hex(), anyVersion); err != errNotFound {
+				t.Errorf("second Delete() error = %v, want %v", err, errNotFound)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreCreateManyIsAtomic(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryStore()
+	taken, err := s.Create(ctx, &blogItem{Title: "taken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fresh := primitive.NewObjectID()
+
+	tests := []struct {
+		name  string
+		items []*blogItem
+	}{
+		{"taken id", []*blogItem{{ID: fresh}, {ID: taken.ID}}},
+		{"duplicate id", []*blogItem{{ID: fresh}, {ID: fresh}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.CreateMany(ctx, tt.items); err != errAlreadyExists {
+				t.Fatalf("CreateMany() error = %v, want %v", err, errAlreadyExists)
+			}
+			if _, err := s.Read(ctx, fresh.Hex()); err != errNotFound {
+				t.Errorf("Read() of a blog of the failed batch error = %v, want %v", err, errNotFound)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreDeleteTimeHasServerPrecision(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryStore()
+	created, err := s.Create(ctx, &blogItem{AuthorID: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleted, err := s.Delete(ctx, created.ID.Hex(), anyVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := *deleted.DeleteTime; !got.Equal(got.Truncate(time.Millisecond)) || got.Location() != time.UTC {
+		t.Errorf("Delete() delete time = %v, want a UTC time in whole milliseconds like serverTime", got)
+	}
+}
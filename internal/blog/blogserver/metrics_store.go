@@ -0,0 +1,164 @@
+package blogserver
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+// metricsStore is a BlogStore recording how long the operations of another BlogStore take
+type metricsStore struct {
+	store    BlogStore
+	duration prometheus.ObserverVec
+}
+
+func newStoreDuration() *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "blog_store_operation_seconds",
+		Help:    "Time taken by the blog store operations, without the time spent by the callers consuming listed blogs.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"store", "operation", "result"})
+}
+
+// newMetricsStore observes the operations of store in duration labeled with the store backend name
+func newMetricsStore(store BlogStore, backend string, duration *prometheus.HistogramVec) *metricsStore {
+	return &metricsStore{
+		store:    store,
+		duration: duration.MustCurryWith(prometheus.Labels{"store": backend}),
+	}
+}
+
+// operation is a store call being timed. The time spent in callbacks is paused
+// so that a slow client listing blogs does not count as a slow store.
+type operation struct {
+	s       *metricsStore
+	name    string
+	start   time.Time
+	elapsed time.Duration
+}
+
+func (s *metricsStore) start(name string) *operation {
+	return &operation{s: s, name: name, start: time.Now()}
+}
+
+// callback runs fn without counting its time
+func (op *operation) callback(fn func() error) error {
+	op.elapsed += time.Since(op.start)
+	err := fn()
+	op.start = time.Now()
+	return err
+}
+
+func (op *operation) done(err error) {
+	op.elapsed += time.Since(op.start)
+	op.s.duration.WithLabelValues(op.name, storeResult(err)).Observe(op.elapsed.Seconds())
+}
+
+// storeResult names the outcome of a store operation, the errors expected by
+// the handlers are not failures of the store
+func storeResult(err error) string {
+	switch err {
+	case nil:
+		return "ok"
+	case errNotFound, errRevisionNotFound:
+		return "not_found"
+	case errInvalidID, errNotDeleted:
+		return "invalid"
+	case errConflict:
+		return "conflict"
+	case errAlreadyExists:
+		return "already_exists"
+	default:
+		return "error"
+	}
+}
+
+func (s *metricsStore) Create(ctx context.Context, item *blogItem) (*blogItem, error) {
+	op := s.start("create")
+	res, err := s.store.Create(ctx, item)
+	op.done(err)
+	return res, err
+}
+
+func (s *metricsStore) CreateMany(ctx context.Context, items []*blogItem) ([]*blogItem, error) {
+	op := s.start("create_many")
+	res, err := s.store.CreateMany(ctx, items)
+	op.done(err)
+	return res, err
+}
+
+func (s *metricsStore) Read(ctx context.Context, id string) (*blogItem, error) {
+	op := s.start("read")
+	res, err := s.store.Read(ctx, id)
+	op.done(err)
+	return res, err
+}
+
+func (s *metricsStore) Update(ctx context.Context, item *blogItem, editor string) (*blogItem, error) {
+	op := s.start("update")
+	res, err := s.store.Update(ctx, item, editor)
+	op.done(err)
+	return res, err
+}
+
+func (s *metricsStore) Delete(ctx context.Context, id string, version int64) (*blogItem, error) {
+	op := s.start("delete")
+	res, err := s.store.Delete(ctx, id, version)
+	op.done(err)
+	return res, err
+}
+
+func (s *metricsStore) Undelete(ctx context.Context, id string) (*blogItem, error) {
+	op := s.start("undelete")
+	res, err := s.store.Undelete(ctx, id)
+	op.done(err)
+	return res, err
+}
+
+func (s *metricsStore) Purge(ctx context.Context, before time.Time) (int, error) {
+	op := s.start("purge")
+	n, err := s.store.Purge(ctx, before)
+	op.done(err)
+	return n, err
+}
+
+func (s *metricsStore) List(ctx context.Context, opts listOptions, fn func(*blogItem) error) error {
+	op := s.start("list")
+	err := s.store.List(ctx, opts, func(item *blogItem) error {
+		return op.callback(func() error { return fn(item) })
+	})
+	op.done(err)
+	return err
+}
+
+func (s *metricsStore) Search(ctx context.Context, query string, limit int, fn func(*searchHit) error) error {
+	op := s.start("search")
+	err := s.store.Search(ctx, query, limit, func(hit *searchHit) error {
+		return op.callback(func() error { return fn(hit) })
+	})
+	op.done(err)
+	return err
+}
+
+func (s *metricsStore) ListRevisions(ctx context.Context, id string, fn func(*revisionItem) error) error {
+	op := s.start("list_revisions")
+	err := s.store.ListRevisions(ctx, id, func(rev *revisionItem) error {
+		return op.callback(func() error { return fn(rev) })
+	})
+	op.done(err)
+	return err
+}
+
+func (s *metricsStore) ReadRevision(ctx context.Context, id string, version int64) (*revisionItem, error) {
+	op := s.start("read_revision")
+	res, err := s.store.ReadRevision(ctx, id, version)
+	op.done(err)
+	return res, err
+}
+
+func (s *metricsStore) Ping(ctx context.Context) error {
+	op := s.start("ping")
+	err := s.store.Ping(ctx)
+	op.done(err)
+	return err
+}
@@ -0,0 +1,496 @@
+package blogserver
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"regexp"
+	"sync/atomic"
+	"time"
+)
+
+// mongoStore is a BlogStore backed by MongoDB collections
+type mongoStore struct {
+	collection *mongo.Collection
+	// revisions holds the archived versions of the blogs in collection
+	revisions *mongo.Collection
+	// indexed is set once ensureIndexes succeeded, 0 or 1
+	indexed int32
+}
+
+func newMongoStore(db *mongo.Database) *mongoStore {
+	return &mongoStore{
+		collection: db.Collection("blog"),
+		revisions:  db.Collection("blog_revisions"),
+	}
+}
+
+// ensureIndexes creates the indexes the queries of the store rely on, unless
+// they were already created by this store. The database may be down when the
+// server starts, so they are created by the health checks and by the queries
+// needing them rather than on startup.
+func (s *mongoStore) ensureIndexes(ctx context.Context) error {
+	if atomic.LoadInt32(&s.indexed) == 1 {
+		return nil
+	}
+	err := traced(ctx, s.collection, "createIndex", func(ctx context.Context) error {
+		_, err := s.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: "title", Value: "text"}, {Key: "content", Value: "text"}},
+			Options: options.Index().
+				SetName("blog_text").
+				SetWeights(bson.M{"title": titleWeight, "content": 1}),
+		})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("cannot create the indexes: %v", err)
+	}
+	atomic.StoreInt32(&s.indexed, 1)
+	return nil
+}
+
+func (s *mongoStore) Create(ctx context.Context, item *blogItem) (*blogItem, error) {
+	data := *item
+	if data.ID.IsZero() {
+		data.ID = primitive.NewObjectID()
+	}
+	data.Version = 1
+
+	err := traced(ctx, s.collection, "insertOne", func(ctx context.Context) error {
+		_, err := s.collection.InsertOne(ctx, data)
+		return err
+	})
+	if err != nil {
+		if isDuplicateKey(err) {
+			return nil, errAlreadyExists
+		}
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (s *mongoStore) CreateMany(ctx context.Context, items []*blogItem) ([]*blogItem, error) {
+	created := make([]*blogItem, len(items))
+	docs := make([]interface{}, len(items))
+	// generated are the ids made up here, no other blog can have them
+	var generated bson.A
+	chosen := make([]bool, len(items))
+	for i, item := range items {
+		data := *item
+		if data.ID.IsZero() {
+			data.ID = primitive.NewObjectID()
+			generated = append(generated, data.ID)
+		} else {
+			chosen[i] = true
+		}
+		data.Version = 1
+		created[i], docs[i] = &data, data
+	}
+
+	err := traced(ctx, s.collection, "insertMany", func(ctx context.Context) error {
+		_, err := s.collection.InsertMany(ctx, docs)
+		return err
+	})
+	if err == nil {
+		return created, nil
+	}
+
+	// MongoDB has no multi-document atomicity without transactions, so take back
+	// whatever made it in before the failure. Removing the generated ids is always
+	// safe, the ids chosen by the client may belong to blogs stored before and are
+	// never removed.
+	if len(generated) > 0 {
+		delErr := traced(ctx, s.collection, "deleteMany", func(ctx context.Context) error {
+			_, err := s.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": generated}})
+			return err
+		})
+		if delErr != nil {
+			return nil, fmt.Errorf("%w: %v, rollback failed: %v", errPartiallyCreated, err, delErr)
+		}
+	}
+	// the insert is ordered, so the first write error tells which blogs were
+	// inserted, any other failure may have happened after some of them were
+	inserted := len(items)
+	if bwe, ok := err.(mongo.BulkWriteException); ok && len(bwe.WriteErrors) > 0 {
+		inserted = bwe.WriteErrors[0].Index
+	}
+	for i := 0; i < inserted; i++ {
+		if chosen[i] {
+			return nil, fmt.Errorf("%w: %v", errPartiallyCreated, err)
+		}
+	}
+	if isDuplicateKey(err) {
+		return nil, errAlreadyExists
+	}
+	return nil, err
+}
+
+func (s *mongoStore) Read(ctx context.Context, id string) (*blogItem, error) {
+	oid, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &blogItem{}
+	err = traced(ctx, s.collection, "findOne", func(ctx context.Context) error {
+		return s.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(data)
+	})
+	if err == mongo.ErrNoDocuments {
+		return nil, errNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *mongoStore) Update(ctx context.Context, item *blogItem, editor string) (*blogItem, error) {
+	data := *item
+	data.Version++
+
+	// the version in the filter makes the replace a compare-and-swap,
+	// and the document before the replace is the revision to archive
+	filter := versionFilter(item.ID, item.Version)
+	filter["delete_time"] = nil
+
+	old := blogItem{}
+	err := traced(ctx, s.collection, "findOneAndReplace", func(ctx context.Context) error {
+		return s.collection.FindOneAndReplace(ctx, filter, data,
+			options.FindOneAndReplace().SetReturnDocument(options.Before)).Decode(&old)
+	})
+	if err == mongo.ErrNoDocuments {
+		return nil, s.missError(ctx, item.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = traced(ctx, s.revisions, "insertOne", func(ctx context.Context) error {
+		_, err := s.revisions.InsertOne(ctx, newRevisionItem(old, editor))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (s *mongoStore) Delete(ctx context.Context, id string, version int64) (*blogItem, error) {
+	oid, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"_id": oid}
+	if version != anyVersion {
+		filter = versionFilter(oid, version)
+	}
+	filter["delete_time"] = nil
+
+	data := &blogItem{}
+	err = traced(ctx, s.collection, "findOneAndUpdate", func(ctx context.Context) error {
+		return s.collection.FindOneAndUpdate(ctx, filter, bson.M{
+			"$set": bson.M{"delete_time": serverTime()},
+			"$inc": bson.M{"version": 1},
+		}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(data)
+	})
+	if err == mongo.ErrNoDocuments {
+		return nil, s.missError(ctx, oid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *mongoStore) Undelete(ctx context.Context, id string) (*blogItem, error) {
+	oid, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &blogItem{}
+	err = traced(ctx, s.collection, "findOneAndUpdate", func(ctx context.Context) error {
+		return s.collection.FindOneAndUpdate(ctx,
+			bson.M{"_id": oid, "delete_time": bson.M{"$ne": nil}},
+			bson.M{"$unset": bson.M{"delete_time": ""}, "$inc": bson.M{"version": 1}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(data)
+	})
+	if err == mongo.ErrNoDocuments {
+		if _, err := s.Read(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, errNotDeleted
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *mongoStore) Purge(ctx context.Context, before time.Time) (int, error) {
+	filter := bson.M{"delete_time": bson.M{"$lt": before}}
+	var cur *mongo.Cursor
+	err := traced(ctx, s.collection, "find", func(ctx context.Context) (err error) {
+		cur, err = s.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	var ids []primitive.ObjectID
+	for cur.Next(ctx) {
+		data := &blogItem{}
+		if err := cur.Decode(data); err != nil {
+			cur.Close(ctx)
+			return 0, err
+		}
+		ids = append(ids, data.ID)
+	}
+	err = cur.Err()
+	cur.Close(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// delete one by one so a blog undeleted meanwhile keeps its revisions
+	purged := 0
+	for _, oid := range ids {
+		var res *mongo.DeleteResult
+		err := traced(ctx, s.collection, "deleteOne", func(ctx context.Context) (err error) {
+			res, err = s.collection.DeleteOne(ctx, bson.M{"_id": oid, "delete_time": bson.M{"$lt": before}})
+			return err
+		})
+		if err != nil {
+			return purged, err
+		}
+		if res.DeletedCount == 0 {
+			continue
+		}
+		purged++
+		err = traced(ctx, s.revisions, "deleteMany", func(ctx context.Context) error {
+			_, err := s.revisions.DeleteMany(ctx, bson.M{"blog_id": oid})
+			return err
+		})
+		if err != nil {
+			return purged, err
+		}
+	}
+	return purged, nil
+}
+
+// missError tells apart why a versioned write matched no document
+func (s *mongoStore) missError(ctx context.Context, oid primitive.ObjectID) error {
+	var n int64
+	err := traced(ctx, s.collection, "countDocuments", func(ctx context.Context) (err error) {
+		n, err = s.collection.CountDocuments(ctx, bson.M{"_id": oid, "delete_time": nil})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errNotFound
+	}
+	return errConflict
+}
+
+// versionFilter matches the blog with the given id only while it is at version
+func versionFilter(oid primitive.ObjectID, version int64) bson.M {
+	if version == 0 {
+		// blogs written before versioning have no version field at all
+		return bson.M{"_id": oid, "version": bson.M{"$in": bson.A{0, nil}}}
+	}
+	return bson.M{"_id": oid, "version": version}
+}
+
+// duplicateKeyCode is the MongoDB error code of a unique index violation
+const duplicateKeyCode = 11000
+
+// isDuplicateKey reports whether an insert failed because the id was taken
+func isDuplicateKey(err error) bool {
+	var writeErrors []mongo.WriteError
+	switch e := err.(type) {
+	case mongo.WriteException:
+		writeErrors = e.WriteErrors
+	case mongo.BulkWriteException:
+		for _, we := range e.WriteErrors {
+			writeErrors = append(writeErrors, we.WriteError)
+		}
+	}
+	for _, we := range writeErrors {
+		if we.Code == duplicateKeyCode {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *mongoStore) List(ctx context.Context, opts listOptions, fn func(*blogItem) error) error {
+	filter := bson.M{}
+	if opts.AuthorID != "" {
+		filter["author_id"] = opts.AuthorID
+	}
+	if opts.TitlePrefix != "" {
+		filter["title"] = primitive.Regex{Pattern: "^" + regexp.QuoteMeta(opts.TitlePrefix)}
+	}
+
+	sort, after := 1, "$gt"
+	if opts.Descending {
+		sort, after = -1, "$lt"
+	}
+	key := opts.OrderBy.field()
+	if opts.After != nil {
+		if opts.OrderBy == orderByID {
+			filter["_id"] = bson.M{after: opts.After.ID}
+		} else {
+			// resume after the (key, _id) pair of the last listed blog
+			t := opts.After.orderKey(opts.OrderBy)
+			filter["$or"] = bson.A{
+				bson.M{key: bson.M{after: t}},
+				bson.M{key: t, "_id": bson.M{after: opts.After.ID}},
+			}
+		}
+	}
+	switch opts.Trash {
+	case trashExclude:
+		filter["delete_time"] = nil
+	case trashOnly:
+		filter["delete_time"] = bson.M{"$ne": nil}
+	}
+
+	order := bson.D{{Key: "_id", Value: sort}}
+	if opts.OrderBy != orderByID {
+		order = append(bson.D{{Key: key, Value: sort}}, order...)
+	}
+	findOpts := options.Find().SetSort(order)
+	if opts.Limit > 0 {
+		findOpts.SetLimit(int64(opts.Limit))
+	}
+
+	var cur *mongo.Cursor
+	err := traced(ctx, s.collection, "find", func(ctx context.Context) (err error) {
+		cur, err = s.collection.Find(ctx, filter, findOpts)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		data := &blogItem{}
+		if err := cur.Decode(data); err != nil {
+			return err
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
+
+func (s *mongoStore) Search(ctx context.Context, query string, limit int, fn func(*searchHit) error) error {
+	// $text queries fail without the text index
+	if err := s.ensureIndexes(ctx); err != nil {
+		return err
+	}
+	score := bson.M{"$meta": "textScore"}
+	var cur *mongo.Cursor
+	err := traced(ctx, s.collection, "find", func(ctx context.Context) (err error) {
+		cur, err = s.collection.Find(ctx,
+			bson.M{"$text": bson.M{"$search": query}, "delete_time": nil},
+			options.Find().
+				SetProjection(bson.M{"score": score}).
+				SetSort(bson.D{{Key: "score", Value: score}}).
+				SetLimit(int64(limit)))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		data := &struct {
+			blogItem `bson:",inline"`
+			Score    float64 `bson:"score"`
+		}{}
+		if err := cur.Decode(data); err != nil {
+			return err
+		}
+		if err := fn(&searchHit{Blog: data.blogItem, Score: data.Score}); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
+
+func (s *mongoStore) ListRevisions(ctx context.Context, id string, fn func(*revisionItem) error) error {
+	oid, err := parseID(id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.Read(ctx, id); err != nil {
+		return err
+	}
+
+	var cur *mongo.Cursor
+	err = traced(ctx, s.revisions, "find", func(ctx context.Context) (err error) {
+		cur, err = s.revisions.Find(ctx, bson.M{"blog_id": oid},
+			options.Find().SetSort(bson.D{{Key: "blog.version", Value: -1}}))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		rev := &revisionItem{}
+		if err := cur.Decode(rev); err != nil {
+			return err
+		}
+		if err := fn(rev); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
+
+func (s *mongoStore) ReadRevision(ctx context.Context, id string, version int64) (*revisionItem, error) {
+	oid, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.Read(ctx, id); err != nil {
+		return nil, err
+	}
+
+	rev := &revisionItem{}
+	err = traced(ctx, s.revisions, "findOne", func(ctx context.Context) error {
+		return s.revisions.FindOne(ctx, bson.M{"blog_id": oid, "blog.version": version}).Decode(rev)
+	})
+	if err == mongo.ErrNoDocuments {
+		return nil, errRevisionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+// Ping checks the primary of the replica set, which every write goes to, is
+// reachable and that the indexes exist, creating them the first time. The
+// store is reported unavailable until they do, so that the health checks stay
+// NOT_SERVING while searching would fail.
+func (s *mongoStore) Ping(ctx context.Context) error {
+	if err := s.collection.Database().Client().Ping(ctx, readpref.Primary()); err != nil {
+		return err
+	}
+	return s.ensureIndexes(ctx)
+}
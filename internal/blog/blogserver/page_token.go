@@ -0,0 +1,82 @@
+package blogserver
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/naraycitra/grpc-go-adventure/internal/blog/blogpb"
+	"time"
+)
+
+const (
+	// defaultPageSize is used when ListBlogRequest.page_size is 0
+	defaultPageSize = 100
+	// maxPageSize caps ListBlogRequest.page_size
+	maxPageSize = 1000
+)
+
+var errInvalidPageToken = errors.New("invalid page token")
+
+// pageToken is the decoded form of ListBlog's opaque page tokens. It records
+// the request filters so a token cannot be replayed against another listing.
+type pageToken struct {
+	After string `json:"after"`
+	// AfterTime is the order_by key of the last listed blog
+	AfterTime   time.Time        `json:"after_time"`
+	AuthorID    string           `json:"author_id,omitempty"`
+	TitlePrefix string           `json:"title_prefix,omitempty"`
+	SortOrder   blogpb.SortOrder `json:"sort_order,omitempty"`
+	ShowDeleted bool             `json:"show_deleted,omitempty"`
+	OrderBy     blogpb.OrderBy   `json:"order_by,omitempty"`
+}
+
+func newPageToken(req *blogpb.ListBlogRequest, order orderBy, last *blogItem) pageToken {
+	return pageToken{
+		After:       last.ID.Hex(),
+		AfterTime:   last.orderKey(order),
+		AuthorID:    req.GetAuthorId(),
+		TitlePrefix: req.GetTitlePrefix(),
+		SortOrder:   req.GetSortOrder(),
+		ShowDeleted: req.GetShowDeleted(),
+		OrderBy:     req.GetOrderBy(),
+	}
+}
+
+func (t pageToken) encode() string {
+	b, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodePageToken parses the page_token of req and checks it was issued for the same filters
+func decodePageToken(req *blogpb.ListBlogRequest) (pageToken, error) {
+	var t pageToken
+	b, err := base64.RawURLEncoding.DecodeString(req.GetPageToken())
+	if err != nil {
+		return t, errInvalidPageToken
+	}
+	if err := json.Unmarshal(b, &t); err != nil {
+		return t, errInvalidPageToken
+	}
+	if t.AuthorID != req.GetAuthorId() || t.TitlePrefix != req.GetTitlePrefix() ||
+		t.SortOrder != req.GetSortOrder() || t.ShowDeleted != req.GetShowDeleted() ||
+		t.OrderBy != req.GetOrderBy() {
+		return t, errInvalidPageToken
+	}
+	return t, nil
+}
+
+// cursor returns the last listed blog as far as listOptions.After needs it
+func (t pageToken) cursor(order orderBy) (*blogItem, error) {
+	oid, err := parseID(t.After)
+	if err != nil {
+		return nil, errInvalidPageToken
+	}
+	item := &blogItem{ID: oid}
+	switch order {
+	case orderByCreateTime:
+		item.CreateTime = t.AfterTime
+	case orderByUpdateTime:
+		item.UpdateTime = t.AfterTime
+	}
+	return item, nil
+}
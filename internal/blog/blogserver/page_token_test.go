@@ -0,0 +1,82 @@
+package blogserver
+
+import (
+	"github.com/naraycitra/grpc-go-adventure/internal/blog/blogpb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"testing"
+	"time"
+)
+
+func TestPageTokenRoundTrip(t *testing.T) {
+	created := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	last := &blogItem{
+		ID:         primitive.NewObjectID(),
+		CreateTime: created,
+		UpdateTime: created.Add(time.Hour),
+	}
+
+	tests := []struct {
+		name     string
+		req      *blogpb.ListBlogRequest
+		order    orderBy
+		wantTime time.Time
+	}{
+		{"by id", &blogpb.ListBlogRequest{}, orderByID, time.Time{}},
+		{"by create time", &blogpb.ListBlogRequest{OrderBy: blogpb.OrderBy_ORDER_BY_CREATE_TIME}, orderByCreateTime, created},
+		{"by update time with filters", &blogpb.ListBlogRequest{
+			AuthorId:    "alice",
+			TitlePrefix: "Go",
+			SortOrder:   blogpb.SortOrder_DESCENDING,
+			ShowDeleted: true,
+			OrderBy:     blogpb.OrderBy_ORDER_BY_UPDATE_TIME,
+		}, orderByUpdateTime, created.Add(time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.PageToken = newPageToken(tt.req, tt.order, last).encode()
+			token, err := decodePageToken(tt.req)
+			if err != nil {
+				t.Fatalf("decodePageToken() error = %v", err)
+			}
+			cursor, err := token.cursor(tt.order)
+			if err != nil {
+				t.Fatalf("cursor() error = %v", err)
+			}
+			if cursor.ID != last.ID {
+				t.Errorf("cursor().ID = %v, want %v", cursor.ID, last.ID)
+			}
+			if got := cursor.orderKey(tt.order); !got.Equal(tt.wantTime) {
+				t.Errorf("cursor().orderKey() = %v, want %v", got, tt.wantTime)
+			}
+		})
+	}
+}
+
+func TestDecodePageTokenRejects(t *testing.T) {
+	issued := &blogpb.ListBlogRequest{AuthorId: "alice", TitlePrefix: "Go"}
+	token := newPageToken(issued, orderByID, &blogItem{ID: primitive.NewObjectID()}).encode()
+
+	tests := []struct {
+		name string
+		req  *blogpb.ListBlogRequest
+	}{
+		{"not base64", &blogpb.ListBlogRequest{AuthorId: "alice", TitlePrefix: "Go", PageToken: "not a token!"}},
+		{"not json", &blogpb.ListBlogRequest{AuthorId: "alice", TitlePrefix: "Go", PageToken: "bm90IGpzb24"}},
+		{"other author", &blogpb.ListBlogRequest{AuthorId: "bob", TitlePrefix: "Go", PageToken: token}},
+		{"other title prefix", &blogpb.ListBlogRequest{AuthorId: "alice", PageToken: token}},
+		{"other sort order", &blogpb.ListBlogRequest{AuthorId: "alice", TitlePrefix: "Go", SortOrder: blogpb.SortOrder_DESCENDING, PageToken: token}},
+		{"other order by", &blogpb.ListBlogRequest{AuthorId: "alice", TitlePrefix: "Go", OrderBy: blogpb.OrderBy_ORDER_BY_CREATE_TIME, PageToken: token}},
+		{"showing deleted blogs", &blogpb.ListBlogRequest{AuthorId: "alice", TitlePrefix: "Go", ShowDeleted: true, PageToken: token}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodePageToken(tt.req); err != errInvalidPageToken {
+				t.Errorf("decodePageToken() error = %v, want %v", err, errInvalidPageToken)
+			}
+		})
+	}
+
+	if _, err := (pageToken{After: "not an id"}).cursor(orderByID); err != errInvalidPageToken {
+		t.Errorf("cursor() error = %v, want %v", err, errInvalidPageToken)
+	}
+}
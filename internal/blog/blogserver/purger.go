@@ -0,0 +1,30 @@
+package blogserver
+
+import (
+	"context"
+	"time"
+)
+
+// runPurger permanently removes the blogs that have been in the trash for longer than
+// retention, checking every interval until ctx is done
+func runPurger(ctx context.Context, store BlogStore, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		purged, err := store.Purge(ctx, time.Now().Add(-retention))
+		if err != nil {
+			sugar.Errorf("error while purging trashed blogs: %v", err)
+			continue
+		}
+		if purged > 0 {
+			sugar.Infof("Purged %d trashed blogs", purged)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package blogserver
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// titleWeight makes a term in the title count as much as this many in the content
+	titleWeight = 3
+	// snippetRadius is how many runes of content are kept around the first match
+	snippetRadius  = 80
+	highlightStart = "<em>"
+	highlightEnd   = "</em>"
+)
+
+// searchHit is a blog matching a search query and its relevance
+type searchHit struct {
+	Blog  blogItem
+	Score float64
+}
+
+// token is a lower cased word of a text and where it starts and ends, in bytes
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize splits text into lower cased words of letters and digits
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if word && start < 0 {
+			start = i
+		}
+		if !word && start >= 0 {
+			tokens = append(tokens, token{strings.ToLower(text[start:i]), start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{strings.ToLower(text[start:]), start, len(text)})
+	}
+	return tokens
+}
+
+// queryTerms returns the distinct terms of a search query
+func queryTerms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, t := range tokenize(query) {
+		if !seen[t.term] {
+			seen[t.term] = true
+			terms = append(terms, t.term)
+		}
+	}
+	return terms
+}
+
+// invertedIndex maps every term to the blogs containing it and its weighted frequency.
+// It is not safe for concurrent use, memoryStore guards it with its own lock.
+type invertedIndex struct {
+	postings map[string]map[primitive.ObjectID]int
+	// terms remembers the indexed terms of every blog so it can be removed again
+	terms map[primitive.ObjectID][]string
+}
+
+func newInvertedIndex() *invertedIndex {
+	return &invertedIndex{
+		postings: make(map[string]map[primitive.ObjectID]int),
+		terms:    make(map[primitive.ObjectID][]string),
+	}
+}
+
+// add indexes the title and content of a blog, replacing what was indexed for it before
+func (idx *invertedIndex) add(item *blogItem) {
+	idx.remove(item.ID)
+
+	freq := make(map[string]int)
+	for _, t := range tokenize(item.Title) {
+		freq[t.term] += titleWeight
+	}
+	for _, t := range tokenize(item.Content) {
+		freq[t.term]++
+	}
+
+	terms := make([]string, 0, len(freq))
+	for term, n := range freq {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[primitive.ObjectID]int)
+		}
+		idx.postings[term][item.ID] = n
+		terms = append(terms, term)
+	}
+	idx.terms[item.ID] = terms
+}
+
+func (idx *invertedIndex) remove(oid primitive.ObjectID) {
+	for _, term := range idx.terms[oid] {
+		delete(idx.postings[term], oid)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	delete(idx.terms, oid)
+}
+
+// search scores every blog containing at least one of the terms with tf-idf and
+// returns their ids, best match first
+func (idx *invertedIndex) search(terms []string) ([]primitive.ObjectID, map[primitive.ObjectID]float64) {
+	scores := make(map[primitive.ObjectID]float64)
+	docs := float64(len(idx.terms))
+	for _, term := range terms {
+		postings := idx.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+		idf := math.Log(1 + docs/float64(len(postings)))
+		for oid, n := range postings {
+			scores[oid] += (1 + math.Log(float64(n))) * idf
+		}
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(scores))
+	for oid := range scores {
+		ids = append(ids, oid)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i].Hex() > ids[j].Hex()
+	})
+	return ids, scores
+}
+
+// highlight wraps every word of text matching one of the terms in highlightStart and highlightEnd
+func highlight(text string, terms []string) string {
+	match := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		match[term] = true
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, t := range tokenize(text) {
+		if !match[t.term] {
+			continue
+		}
+		b.WriteString(text[last:t.start])
+		b.WriteString(highlightStart)
+		b.WriteString(text[t.start:t.end])
+		b.WriteString(highlightEnd)
+		last = t.end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// snippet returns the highlighted part of text around the first word matching one of the terms,
+// or the beginning of text when nothing matches
+func snippet(text string, terms []string) string {
+	match := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		match[term] = true
+	}
+
+	runes := []rune(text)
+	// the matched word spans runes[center:centerEnd]
+	center, centerEnd := 0, 0
+	for _, t := range tokenize(text) {
+		if match[t.term] {
+			center = utf8.RuneCountInString(text[:t.start])
+			centerEnd = center + utf8.RuneCountInString(text[t.start:t.end])
+			break
+		}
+	}
+
+	start, end := center-snippetRadius, center+snippetRadius
+	if start < 0 {
+		start = 0
+	}
+	if end < centerEnd {
+		end = centerEnd
+	}
+	if end > len(runes) {
+		end = len(runes)
+	}
+	// do not cut words in half at the edges, unless there is no space to cut at
+	// before or after the match
+	for i := start; i > 0 && i < center; i++ {
+		if unicode.IsSpace(runes[i-1]) {
+			start = i
+			break
+		}
+	}
+	for i := end; i < len(runes) && i > centerEnd; i-- {
+		if unicode.IsSpace(runes[i]) {
+			end = i
+			break
+		}
+	}
+
+	s := highlight(string(runes[start:end]), terms)
+	if start > 0 {
+		s = "..." + s
+	}
+	if end < len(runes) {
+		s += "..."
+	}
+	return s
+}
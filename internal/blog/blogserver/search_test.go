@@ -0,0 +1,185 @@
+package blogserver
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []token
+	}{
+		{"", nil},
+		{"  ,; ", nil},
+		{"Hello, World", []token{{"hello", 0, 5}, {"world", 7, 12}}},
+		{"gRPC-go v1.42", []token{{"grpc", 0, 4}, {"go", 5, 7}, {"v1", 8, 10}, {"42", 11, 13}}},
+		// offsets are in bytes, ü and ö take two
+		{"Über schön!", []token{{"über", 0, 5}, {"schön", 6, 12}}},
+		{"日本語 text", []token{{"日本語", 0, 9}, {"text", 10, 14}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenize(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", nil},
+		{"Go go GO", []string{"go"}},
+		{"grpc, Go and gRPC", []string{"grpc", "go", "and"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := queryTerms(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("queryTerms(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		text  string
+		terms []string
+		want  string
+	}{
+		{"Go is fun", []string{"rust"}, "Go is fun"},
+		{"Go is fun", []string{"go"}, "<em>Go</em> is fun"},
+		{"Go is fun", []string{"fun"}, "Go is <em>fun</em>"},
+		{"go, GO, gopher", []string{"go"}, "<em>go</em>, <em>GO</em>, gopher"},
+		{"Schöne Grüße", []string{"grüße", "schöne"}, "<em>Schöne</em> <em>Grüße</em>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := highlight(tt.text, tt.terms); got != tt.want {
+				t.Errorf("highlight(%q, %v) = %q, want %q", tt.text, tt.terms, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	words := strings.Repeat("word ", 40)
+	umlauts := strings.Repeat("ünïcödé ", 30)
+	noSpaces := strings.Repeat("x,", 100)
+
+	tests := []struct {
+		name       string
+		text       string
+		terms      []string
+		wantMatch  string
+		wantPrefix bool
+		wantSuffix bool
+	}{
+		{"short text", "Go is fun", []string{"fun"}, "<em>fun</em>", false, false},
+		{"short text without match", "Go is fun", []string{"rust"}, "", false, false},
+		{"match at the start", "target " + words, []string{"target"}, "<em>target</em>", false, true},
+		{"match at the end", words + "target", []string{"target"}, "<em>target</em>", true, false},
+		{"match in the middle", words + "target " + words, []string{"target"}, "<em>target</em>", true, true},
+		{"multibyte text", umlauts + "tärget " + umlauts, []string{"tärget"}, "<em>tärget</em>", true, true},
+		{"no match", words, []string{"rust"}, "", false, true},
+		{"no spaces", "target," + noSpaces, []string{"target"}, "<em>target</em>", false, true},
+		{"no spaces before the match", noSpaces + "target", []string{"target"}, "<em>target</em>", true, false},
+		{"no spaces without match", noSpaces, []string{"rust"}, "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := snippet(tt.text, tt.terms)
+			if !strings.Contains(got, tt.wantMatch) {
+				t.Errorf("snippet() = %q, want it to contain %q", got, tt.wantMatch)
+			}
+			if strings.HasPrefix(got, "...") != tt.wantPrefix {
+				t.Errorf("snippet() = %q, want a leading ellipsis: %v", got, tt.wantPrefix)
+			}
+			if strings.HasSuffix(got, "...") != tt.wantSuffix {
+				t.Errorf("snippet() = %q, want a trailing ellipsis: %v", got, tt.wantSuffix)
+			}
+
+			plain := strings.TrimSuffix(strings.TrimPrefix(got, "..."), "...")
+			plain = strings.NewReplacer(highlightStart, "", highlightEnd, "").Replace(plain)
+			if plain == "" {
+				t.Fatalf("snippet() = %q, want some text", got)
+			}
+			if n := utf8.RuneCountInString(plain); n > 2*snippetRadius+len("target") {
+				t.Errorf("snippet() has %d runes, want at most %d", n, 2*snippetRadius+len("target"))
+			}
+			start := strings.Index(tt.text, plain)
+			if start < 0 {
+				t.Fatalf("snippet() = %q is not a part of the text", got)
+			}
+			// the edges are cut between words whenever the text has spaces to cut at
+			if strings.Contains(tt.text, " ") {
+				if before, _ := utf8.DecodeLastRuneInString(tt.text[:start]); start > 0 && isWordRune(before) {
+					t.Errorf("snippet() = %q starts in the middle of a word", got)
+				}
+				if after, _ := utf8.DecodeRuneInString(tt.text[start+len(plain):]); start+len(plain) < len(tt.text) && isWordRune(after) {
+					t.Errorf("snippet() = %q ends in the middle of a word", got)
+				}
+			}
+		})
+	}
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func TestInvertedIndexSearch(t *testing.T) {
+	titled := &blogItem{ID: primitive.NewObjectID(), Title: "Golang tips", Content: "misc"}
+	once := &blogItem{ID: primitive.NewObjectID(), Title: "misc", Content: "some golang"}
+	twice := &blogItem{ID: primitive.NewObjectID(), Title: "misc", Content: "golang and more golang"}
+	rare := &blogItem{ID: primitive.NewObjectID(), Title: "misc", Content: "rust"}
+
+	idx := newInvertedIndex()
+	for _, item := range []*blogItem{titled, once, twice, rare} {
+		idx.add(item)
+	}
+
+	tests := []struct {
+		name  string
+		terms []string
+		want  []primitive.ObjectID
+	}{
+		{"no match", []string{"python"}, []primitive.ObjectID{}},
+		{"title weighs more than the content", []string{"golang"}, []primitive.ObjectID{titled.ID, twice.ID, once.ID}},
+		{"rare terms weigh more than repeated common ones", []string{"golang", "rust"}, []primitive.ObjectID{titled.ID, rare.ID, twice.ID, once.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, scores := idx.search(tt.terms)
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("search(%v) = %v, want %v", tt.terms, ids, tt.want)
+			}
+			for _, id := range ids {
+				if scores[id] <= 0 {
+					t.Errorf("search(%v) score of %v = %v, want a positive score", tt.terms, id, scores[id])
+				}
+			}
+		})
+	}
+
+	// updating a blog replaces its terms, removing it drops them
+	idx.add(&blogItem{ID: titled.ID, Title: "Rust tips"})
+	if ids, _ := idx.search([]string{"golang"}); !reflect.DeepEqual(ids, []primitive.ObjectID{twice.ID, once.ID}) {
+		t.Errorf("search() after an update = %v, want %v", ids, []primitive.ObjectID{twice.ID, once.ID})
+	}
+	idx.remove(rare.ID)
+	if ids, _ := idx.search([]string{"rust"}); !reflect.DeepEqual(ids, []primitive.ObjectID{titled.ID}) {
+		t.Errorf("search() after a removal = %v, want %v", ids, []primitive.ObjectID{titled.ID})
+	}
+	if _, ok := idx.postings["misc"][rare.ID]; ok {
+		t.Error("remove() left postings of the removed blog")
+	}
+}
@@ -0,0 +1,745 @@
+// Package blogserver implements the BlogService on top of a BlogStore.
+package blogserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/naraycitra/grpc-go-adventure/internal/auth"
+	"github.com/naraycitra/grpc-go-adventure/internal/blog/blogpb"
+	"github.com/naraycitra/grpc-go-adventure/internal/logging"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// batchSize is how many blogs BatchCreateBlogs inserts at once
+	batchSize = 100
+	// maxBatchItems caps the number of blogs of one BatchCreateBlogs call
+	maxBatchItems  = 10000
+	maxTitleLength = 256
+)
+
+// sugar is the logger of the package outside of requests, set by New. The
+// handlers log to the request logger of their context.
+var sugar *zap.SugaredLogger
+
+type server struct {
+	store BlogStore
+	// events feeds WatchBlogs with the changes made by the other handlers
+	events *eventBus
+}
+
+func (s *server) CreateBlog(ctx context.Context, req *blogpb.CreateBlogRequest) (*blogpb.CreateBlogResponse, error) {
+	logger := logging.FromContext(ctx)
+	logger.Infof("Create Blog: %s", req.GetBlog().GetTitle())
+	blog := req.GetBlog()
+
+	now := serverTime()
+	data, err := s.store.Create(ctx, &blogItem{
+		AuthorID:   authorID(ctx, blog),
+		Title:      blog.GetTitle(),
+		Content:    blog.GetContent(),
+		CreateTime: now,
+		UpdateTime: now,
+	})
+	if err != nil {
+		logger.Errorf("Error while insert data: %v", err)
+		return nil, storeError(err, "cannot insert data")
+	}
+	s.events.publish(blogpb.BlogEventType_CREATED, data)
+
+	return &blogpb.CreateBlogResponse{
+		Blog: dataToBlogPb(data),
+	}, nil
+}
+
+func (s *server) BatchCreateBlogs(stream blogpb.BlogService_BatchCreateBlogsServer) error {
+	logger := logging.FromContext(stream.Context())
+	logger.Info("Batch create blogs")
+
+	var results []*blogpb.BatchCreateBlogsResult
+	// items holds the valid blogs to create and pending the index of their result
+	var items []*blogItem
+	var pending []int
+	allOrNothing, preserveIDs := false, false
+	now := serverTime()
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			logger.Errorf("Error received stream from client: %v", err)
+			return err
+		}
+		if len(results) == 0 {
+			allOrNothing = req.GetAllOrNothing()
+			preserveIDs = req.GetPreserveIds()
+		}
+		if len(results) == maxBatchItems {
+			logger.Errorf("batch is larger than %d blogs", maxBatchItems)
+			return status.Errorf(codes.InvalidArgument, "cannot create more than %d blogs at once", maxBatchItems)
+		}
+
+		result := &blogpb.BatchCreateBlogsResult{Index: int32(len(results))}
+		results = append(results, result)
+		blog := req.GetBlog()
+		if err := validateBlog(blog); err != nil {
+			result.Status = status.New(codes.InvalidArgument, err.Error()).Proto()
+			continue
+		}
+		item := &blogItem{
+			AuthorID:   authorID(stream.Context(), blog),
+			Title:      blog.GetTitle(),
+			Content:    blog.GetContent(),
+			CreateTime: now,
+			UpdateTime: now,
+		}
+		if preserveIDs {
+			if item.ID, err = parseID(blog.GetId()); err != nil {
+				result.Status = status.New(codes.InvalidArgument, err.Error()).Proto()
+				continue
+			}
+		}
+		items = append(items, item)
+		pending = append(pending, int(result.Index))
+	}
+
+	created := func(i int, data *blogItem) {
+		results[pending[i]].Blog = dataToBlogPb(data)
+		results[pending[i]].Status = status.New(codes.OK, "").Proto()
+		s.events.publish(blogpb.BlogEventType_CREATED, data)
+	}
+	failed := func(i int, err error) {
+		results[pending[i]].Status = status.Convert(err).Proto()
+	}
+
+	switch {
+	case allOrNothing && len(pending) < len(results):
+		for i := range pending {
+			failed(i, status.Error(codes.Aborted, "not created because another blog of the batch is invalid"))
+		}
+	case allOrNothing:
+		data, err := s.store.CreateMany(stream.Context(), items)
+		for i := range pending {
+			if err != nil {
+				failed(i, storeError(err, "cannot insert data"))
+			} else {
+				created(i, data[i])
+			}
+		}
+	default:
+		for start := 0; start < len(items); start += batchSize {
+			end := start + batchSize
+			if end > len(items) {
+				end = len(items)
+			}
+			data, err := s.store.CreateMany(stream.Context(), items[start:end])
+			if err == nil {
+				for i := start; i < end; i++ {
+					created(i, data[i-start])
+				}
+				continue
+			}
+			if errors.Is(err, errPartiallyCreated) {
+				// retrying would report the blogs already created as existing
+				logger.Errorf("Error while insert batch: %v", err)
+				for i := start; i < end; i++ {
+					failed(i, storeError(err, "cannot insert data"))
+				}
+				continue
+			}
+			// find out which blogs of the failed batch are to blame
+			logger.Errorf("Error while insert batch, retrying one by one: %v", err)
+			for i := start; i < end; i++ {
+				data, err := s.store.Create(stream.Context(), items[i])
+				if err != nil {
+					failed(i, storeError(err, "cannot insert data"))
+				} else {
+					created(i, data)
+				}
+			}
+		}
+	}
+
+	return stream.SendAndClose(&blogpb.BatchCreateBlogsResponse{
+		Results: results,
+	})
+}
+
+// authorID returns the author of a blog to create: the authenticated caller,
+// or the author_id sent by the client when authentication is disabled
+func authorID(ctx context.Context, blog *blogpb.Blog) string {
+	if p, ok := auth.FromContext(ctx); ok {
+		return p.Subject
+	}
+	return blog.GetAuthorId()
+}
+
+// checkOwner returns PERMISSION_DENIED when the caller is authenticated as someone
+// else than the author of the blog
+func checkOwner(ctx context.Context, data *blogItem) error {
+	p, ok := auth.FromContext(ctx)
+	if !ok || p.Subject == data.AuthorID {
+		return nil
+	}
+	return status.Errorf(codes.PermissionDenied, "%s is not the author of blog %s", p.Subject, data.ID.Hex())
+}
+
+// editor returns who changes a blog: the authenticated caller, or the editor
+// sent by the client when authentication is disabled
+func editor(ctx context.Context, requested string) string {
+	if p, ok := auth.FromContext(ctx); ok {
+		return p.Subject
+	}
+	return requested
+}
+
+// validateBlog checks the client supplied fields of a blog to create
+func validateBlog(blog *blogpb.Blog) error {
+	switch {
+	case blog == nil:
+		return fmt.Errorf("blog is required")
+	case strings.TrimSpace(blog.GetTitle()) == "":
+		return fmt.Errorf("title is required")
+	case utf8.RuneCountInString(blog.GetTitle()) > maxTitleLength:
+		return fmt.Errorf("title is longer than %d characters", maxTitleLength)
+	}
+	return nil
+}
+
+func (s *server) ReadBlog(ctx context.Context, req *blogpb.ReadBlogRequest) (*blogpb.ReadBlogResponse, error) {
+	logger := logging.FromContext(ctx)
+	logger.Infof("Read blog: %s", req.GetBlogId())
+
+	data, err := s.store.Read(ctx, req.GetBlogId())
+	if err == nil && data.deleted() && !req.GetShowDeleted() {
+		err = errNotFound
+	}
+	if err != nil {
+		logger.Errorf("Cannot find blog with ID:%v", err)
+		return nil, storeError(err, "cannot find blog with specified id")
+	}
+	// the trash of an author is private once callers are authenticated
+	if data.deleted() {
+		if err := checkOwner(ctx, data); err != nil {
+			logger.Errorf("cannot read deleted blog: %v", err)
+			return nil, err
+		}
+	}
+
+	return &blogpb.ReadBlogResponse{
+		Blog: dataToBlogPb(data),
+	}, nil
+}
+
+func dataToBlogPb(data *blogItem) *blogpb.Blog {
+	blog := &blogpb.Blog{
+		Id:       data.ID.Hex(),
+		AuthorId: data.AuthorID,
+		Title:    data.Title,
+		Content:  data.Content,
+		Etag:     formatEtag(data.Version),
+	}
+	// times written by the server are always in the valid Timestamp range
+	if data.deleted() {
+		blog.DeleteTime, _ = ptypes.TimestampProto(*data.DeleteTime)
+	}
+	if !data.CreateTime.IsZero() {
+		blog.CreateTime, _ = ptypes.TimestampProto(data.CreateTime)
+	}
+	if !data.UpdateTime.IsZero() {
+		blog.UpdateTime, _ = ptypes.TimestampProto(data.UpdateTime)
+	}
+	return blog
+}
+
+// serverTime returns the current time at the millisecond precision MongoDB stores,
+// so the memory and mongo stores hand out identical timestamps
+func serverTime() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+func formatEtag(version int64) string {
+	return strconv.FormatInt(version, 10)
+}
+
+// parseEtag returns the blog version encoded in an etag issued by formatEtag
+func parseEtag(etag string) (int64, error) {
+	version, err := strconv.ParseInt(etag, 10, 64)
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("malformed etag %q", etag)
+	}
+	return version, nil
+}
+
+// storeError converts an error returned by the BlogStore into a gRPC status
+func storeError(err error, msg string) error {
+	switch {
+	case errors.Is(err, errInvalidID):
+		return status.Errorf(codes.InvalidArgument, "%s: %v", msg, err)
+	case errors.Is(err, errNotFound), errors.Is(err, errRevisionNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	case errors.Is(err, errNotDeleted):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", msg, err)
+	case errors.Is(err, errConflict):
+		return status.Errorf(codes.Aborted, "%s: %v", msg, err)
+	case errors.Is(err, errAlreadyExists):
+		return status.Errorf(codes.AlreadyExists, "%s: %v", msg, err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
+
+func (s *server) UpdateBlog(ctx context.Context, req *blogpb.UpdateBlogRequest) (*blogpb.UpdateBlogResponse, error) {
+	logger := logging.FromContext(ctx)
+	logger.Infof("Update blog: %v", req.GetBlog())
+	blog := req.GetBlog()
+
+	data, err := s.store.Read(ctx, blog.GetId())
+	if err == nil && data.deleted() {
+		err = errNotFound
+	}
+	if err != nil {
+		logger.Errorf("cannot find blog with specified id:%v", err)
+		return nil, storeError(err, "cannot find blog with specified id")
+	}
+	if err := checkOwner(ctx, data); err != nil {
+		logger.Errorf("cannot update blog: %v", err)
+		return nil, err
+	}
+
+	if blog.GetEtag() != "" {
+		version, err := parseEtag(blog.GetEtag())
+		if err != nil {
+			logger.Errorf("invalid etag: %v", err)
+			return nil, status.Errorf(codes.InvalidArgument, "invalid etag: %v", err)
+		}
+		if version != data.Version {
+			logger.Errorf("etag mismatch for blog %s", blog.GetId())
+			return nil, storeError(errConflict, "cannot update blog")
+		}
+	}
+
+	// we update internal struct with the fields listed in the mask only
+	author := data.AuthorID
+	if err := applyUpdateMask(data, blog, req.GetUpdateMask()); err != nil {
+		logger.Errorf("invalid update mask: %v", err)
+		return nil, status.Errorf(codes.InvalidArgument, "invalid update mask: %v", err)
+	}
+	// authenticated authors cannot give their blogs away, leaving the author
+	// empty in a full update keeps it
+	if _, ok := auth.FromContext(ctx); ok && data.AuthorID != author {
+		if data.AuthorID != "" {
+			logger.Errorf("cannot change the author of blog %s to %s", blog.GetId(), data.AuthorID)
+			return nil, status.Errorf(codes.PermissionDenied, "cannot change the author of blog %s from %s to %s", blog.GetId(), author, data.AuthorID)
+		}
+		data.AuthorID = author
+	}
+	data.UpdateTime = serverTime()
+
+	data, err = s.store.Update(ctx, data, editor(ctx, req.GetEditor()))
+	if err != nil {
+		logger.Errorf("cannot update blog: %v", err)
+		return nil, storeError(err, "cannot update blog")
+	}
+	s.events.publish(blogpb.BlogEventType_UPDATED, data)
+
+	return &blogpb.UpdateBlogResponse{
+		Blog: dataToBlogPb(data),
+	}, nil
+}
+
+func (s *server) DeleteBlog(ctx context.Context, req *blogpb.DeleteBlogRequest) (*blogpb.DeleteBlogResponse, error) {
+	logger := logging.FromContext(ctx)
+	logger.Infof("Delete blog: %v", req.GetBlogId())
+
+	version := anyVersion
+	if req.GetEtag() != "" {
+		var err error
+		if version, err = parseEtag(req.GetEtag()); err != nil {
+			logger.Errorf("invalid etag: %v", err)
+			return nil, status.Errorf(codes.InvalidArgument, "invalid etag: %v", err)
+		}
+	}
+
+	data, err := s.store.Read(ctx, req.GetBlogId())
+	if err == nil && data.deleted() {
+		err = errNotFound
+	}
+	if err != nil {
+		logger.Errorf("cannot delete blog: %v", err)
+		return nil, storeError(err, "cannot delete blog")
+	}
+	if err := checkOwner(ctx, data); err != nil {
+		logger.Errorf("cannot delete blog: %v", err)
+		return nil, err
+	}
+
+	data, err = s.store.Delete(ctx, req.GetBlogId(), version)
+	if err != nil {
+		logger.Errorf("cannot delete blog: %v", err)
+		return nil, storeError(err, "cannot delete blog")
+	}
+	s.events.publish(blogpb.BlogEventType_DELETED, data)
+
+	return &blogpb.DeleteBlogResponse{
+		BlogId: req.GetBlogId(),
+	}, nil
+}
+
+func (s *server) UndeleteBlog(ctx context.Context, req *blogpb.UndeleteBlogRequest) (*blogpb.UndeleteBlogResponse, error) {
+	logger := logging.FromContext(ctx)
+	logger.Infof("Undelete blog: %v", req.GetBlogId())
+
+	data, err := s.store.Read(ctx, req.GetBlogId())
+	if err != nil {
+		logger.Errorf("cannot undelete blog: %v", err)
+		return nil, storeError(err, "cannot undelete blog")
+	}
+	if err := checkOwner(ctx, data); err != nil {
+		logger.Errorf("cannot undelete blog: %v", err)
+		return nil, err
+	}
+
+	data, err = s.store.Undelete(ctx, req.GetBlogId())
+	if err != nil {
+		logger.Errorf("cannot undelete blog: %v", err)
+		return nil, storeError(err, "cannot undelete blog")
+	}
+	s.events.publish(blogpb.BlogEventType_UPDATED, data)
+
+	return &blogpb.UndeleteBlogResponse{
+		Blog: dataToBlogPb(data),
+	}, nil
+}
+
+func (s *server) ListDeletedBlogs(req *blogpb.ListDeletedBlogsRequest, stream blogpb.BlogService_ListDeletedBlogsServer) error {
+	logger := logging.FromContext(stream.Context())
+	logger.Info("List deleted blogs request")
+
+	// authenticated callers only see their own trash
+	opts := listOptions{Trash: trashOnly}
+	if p, ok := auth.FromContext(stream.Context()); ok {
+		opts.AuthorID = p.Subject
+	}
+	err := s.store.List(stream.Context(), opts, func(data *blogItem) error {
+		return stream.Send(&blogpb.ListDeletedBlogsResponse{
+			Blog: dataToBlogPb(data),
+		})
+	})
+	if err != nil {
+		logger.Errorf("error while listing deleted blogs: %v", err)
+		return storeError(err, "error while listing deleted blogs")
+	}
+	return nil
+}
+
+func (s *server) ListBlog(req *blogpb.ListBlogRequest, stream blogpb.BlogService_ListBlogServer) error {
+	logger := logging.FromContext(stream.Context())
+	logger.Infof("List blog request: %v", req)
+
+	opts, pageSize, err := listOptionsFromRequest(req)
+	if err != nil {
+		logger.Errorf("invalid list request: %v", err)
+		return status.Errorf(codes.InvalidArgument, "invalid list request: %v", err)
+	}
+	if p, ok := auth.FromContext(stream.Context()); ok && req.GetShowDeleted() && req.GetAuthorId() != p.Subject {
+		logger.Errorf("cannot list deleted blogs of others: %s", p.Subject)
+		return status.Errorf(codes.PermissionDenied, "show_deleted requires author_id to be %s", p.Subject)
+	}
+	// fetch one extra blog to find out whether there is a next page
+	opts.Limit = pageSize + 1
+
+	send := func(data *blogItem, more bool) error {
+		res := &blogpb.ListBlogResponse{
+			Blog: dataToBlogPb(data),
+		}
+		if more {
+			res.NextPageToken = newPageToken(req, opts.OrderBy, data).encode()
+		}
+		return stream.Send(res)
+	}
+
+	// hold back the latest blog until we know whether another one follows it
+	var prev *blogItem
+	count, more := 0, false
+	err = s.store.List(stream.Context(), opts, func(data *blogItem) error {
+		count++
+		if count > pageSize {
+			more = true
+			return nil
+		}
+		if prev != nil {
+			if err := send(prev, true); err != nil {
+				return err
+			}
+		}
+		prev = data
+		return nil
+	})
+	if err == nil && prev != nil {
+		err = send(prev, more)
+	}
+	if err != nil {
+		logger.Errorf("error while listing blogs: %v", err)
+		return storeError(err, "error while listing blogs")
+	}
+	return nil
+}
+
+// listOptionsFromRequest validates req and converts it into store listOptions and a page size
+func listOptionsFromRequest(req *blogpb.ListBlogRequest) (listOptions, int, error) {
+	opts := listOptions{
+		AuthorID:    req.GetAuthorId(),
+		TitlePrefix: req.GetTitlePrefix(),
+		Descending:  req.GetSortOrder() == blogpb.SortOrder_DESCENDING,
+	}
+	switch req.GetOrderBy() {
+	case blogpb.OrderBy_ORDER_BY_ID:
+		opts.OrderBy = orderByID
+	case blogpb.OrderBy_ORDER_BY_CREATE_TIME:
+		opts.OrderBy = orderByCreateTime
+	case blogpb.OrderBy_ORDER_BY_UPDATE_TIME:
+		opts.OrderBy = orderByUpdateTime
+	default:
+		return opts, 0, fmt.Errorf("unknown order_by %v", req.GetOrderBy())
+	}
+	if req.GetShowDeleted() {
+		opts.Trash = trashInclude
+	}
+
+	pageSize := int(req.GetPageSize())
+	switch {
+	case pageSize < 0:
+		return opts, 0, fmt.Errorf("page_size must not be negative")
+	case pageSize == 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+
+	if req.GetPageToken() != "" {
+		token, err := decodePageToken(req)
+		if err != nil {
+			return opts, 0, err
+		}
+		if opts.After, err = token.cursor(opts.OrderBy); err != nil {
+			return opts, 0, err
+		}
+	}
+	return opts, pageSize, nil
+}
+
+func (s *server) SearchBlogs(req *blogpb.SearchBlogsRequest, stream blogpb.BlogService_SearchBlogsServer) error {
+	logger := logging.FromContext(stream.Context())
+	logger.Infof("Search blogs: %q", req.GetQuery())
+
+	terms := queryTerms(req.GetQuery())
+	if len(terms) == 0 {
+		logger.Error("empty search query")
+		return status.Errorf(codes.InvalidArgument, "query must contain at least one word")
+	}
+
+	pageSize := int(req.GetPageSize())
+	switch {
+	case pageSize < 0:
+		return status.Errorf(codes.InvalidArgument, "page_size must not be negative")
+	case pageSize == 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+
+	err := s.store.Search(stream.Context(), req.GetQuery(), pageSize, func(hit *searchHit) error {
+		return stream.Send(&blogpb.SearchBlogsResponse{
+			Blog:           dataToBlogPb(&hit.Blog),
+			Score:          hit.Score,
+			TitleHighlight: highlight(hit.Blog.Title, terms),
+			ContentSnippet: snippet(hit.Blog.Content, terms),
+		})
+	})
+	if err != nil {
+		logger.Errorf("error while searching blogs: %v", err)
+		return storeError(err, "error while searching blogs")
+	}
+	return nil
+}
+
+func (s *server) WatchBlogs(req *blogpb.WatchBlogsRequest, stream blogpb.BlogService_WatchBlogsServer) error {
+	logger := logging.FromContext(stream.Context())
+	logger.Infof("Watch blogs from cursor %q", req.GetCursor())
+
+	replay, sub, err := s.events.subscribe(req.GetCursor())
+	switch {
+	case errors.Is(err, errInvalidCursor):
+		return status.Errorf(codes.InvalidArgument, "cannot watch blogs: %v", err)
+	case errors.Is(err, errCursorExpired):
+		return status.Errorf(codes.OutOfRange, "cannot watch blogs: %v", err)
+	case errors.Is(err, errBusClosed):
+		return status.Errorf(codes.Unavailable, "cannot watch blogs: %v", err)
+	case err != nil:
+		return status.Errorf(codes.Internal, "cannot watch blogs: %v", err)
+	}
+	defer s.events.unsubscribe(sub)
+
+	for _, ev := range replay {
+		if err := s.sendEvent(stream, ev); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return status.Errorf(codes.Canceled, "watcher disconnected: %v", stream.Context().Err())
+		case ev, ok := <-sub.ch:
+			if !ok && sub.closed {
+				return status.Errorf(codes.Unavailable, "the server is stopping, watch again from the last cursor")
+			}
+			if !ok {
+				logger.Info("Dropping slow blog watcher")
+				return status.Errorf(codes.ResourceExhausted, "watcher fell behind, resume from the last cursor")
+			}
+			if err := s.sendEvent(stream, ev); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *server) sendEvent(stream blogpb.BlogService_WatchBlogsServer, ev blogEvent) error {
+	// times written by the server are always in the valid Timestamp range
+	eventTime, _ := ptypes.TimestampProto(ev.Time)
+	return stream.Send(&blogpb.WatchBlogsResponse{
+		Type:      ev.Type,
+		Blog:      dataToBlogPb(&ev.Blog),
+		Cursor:    s.events.cursor(ev),
+		EventTime: eventTime,
+	})
+}
+
+func (s *server) ListBlogRevisions(req *blogpb.ListBlogRevisionsRequest, stream blogpb.BlogService_ListBlogRevisionsServer) error {
+	logger := logging.FromContext(stream.Context())
+	logger.Infof("List blog revisions: %s", req.GetBlogId())
+
+	// the history of a trashed blog is hidden like the blog itself
+	data, err := s.store.Read(stream.Context(), req.GetBlogId())
+	if err == nil && data.deleted() {
+		err = errNotFound
+	}
+	if err != nil {
+		logger.Errorf("cannot find blog with specified id:%v", err)
+		return storeError(err, "cannot find blog with specified id")
+	}
+
+	err = s.store.ListRevisions(stream.Context(), req.GetBlogId(), func(rev *revisionItem) error {
+		res, err := revisionToPb(rev)
+		if err != nil {
+			return err
+		}
+		return stream.Send(&blogpb.ListBlogRevisionsResponse{
+			Revision: res,
+		})
+	})
+	if err != nil {
+		logger.Errorf("error while listing blog revisions: %v", err)
+		return storeError(err, "error while listing blog revisions")
+	}
+	return nil
+}
+
+func (s *server) GetBlogRevision(ctx context.Context, req *blogpb.GetBlogRevisionRequest) (*blogpb.GetBlogRevisionResponse, error) {
+	logger := logging.FromContext(ctx)
+	logger.Infof("Get blog revision: %s@%d", req.GetBlogId(), req.GetRevisionId())
+
+	data, err := s.store.Read(ctx, req.GetBlogId())
+	if err == nil && data.deleted() {
+		err = errNotFound
+	}
+	if err != nil {
+		logger.Errorf("cannot find blog with specified id:%v", err)
+		return nil, storeError(err, "cannot find blog with specified id")
+	}
+
+	rev, err := s.store.ReadRevision(ctx, req.GetBlogId(), req.GetRevisionId())
+	if err != nil {
+		logger.Errorf("cannot find blog revision: %v", err)
+		return nil, storeError(err, "cannot find blog revision")
+	}
+
+	res, err := revisionToPb(rev)
+	if err != nil {
+		logger.Errorf("cannot convert blog revision: %v", err)
+		return nil, storeError(err, "cannot convert blog revision")
+	}
+	return &blogpb.GetBlogRevisionResponse{
+		Revision: res,
+	}, nil
+}
+
+func (s *server) RestoreBlogRevision(ctx context.Context, req *blogpb.RestoreBlogRevisionRequest) (*blogpb.RestoreBlogRevisionResponse, error) {
+	logger := logging.FromContext(ctx)
+	logger.Infof("Restore blog revision: %s@%d", req.GetBlogId(), req.GetRevisionId())
+
+	rev, err := s.store.ReadRevision(ctx, req.GetBlogId(), req.GetRevisionId())
+	if err != nil {
+		logger.Errorf("cannot find blog revision: %v", err)
+		return nil, storeError(err, "cannot find blog revision")
+	}
+
+	data, err := s.store.Read(ctx, req.GetBlogId())
+	if err == nil && data.deleted() {
+		err = errNotFound
+	}
+	if err != nil {
+		logger.Errorf("cannot find blog with specified id:%v", err)
+		return nil, storeError(err, "cannot find blog with specified id")
+	}
+	if err := checkOwner(ctx, data); err != nil {
+		logger.Errorf("cannot restore blog revision: %v", err)
+		return nil, err
+	}
+
+	// the restored content becomes a new version, the current one is archived.
+	// Authenticated authors keep the blog even if a revision had another author.
+	if _, ok := auth.FromContext(ctx); !ok {
+		data.AuthorID = rev.Blog.AuthorID
+	}
+	data.Title = rev.Blog.Title
+	data.Content = rev.Blog.Content
+	data.UpdateTime = serverTime()
+
+	data, err = s.store.Update(ctx, data, editor(ctx, req.GetEditor()))
+	if err != nil {
+		logger.Errorf("cannot restore blog revision: %v", err)
+		return nil, storeError(err, "cannot restore blog revision")
+	}
+	s.events.publish(blogpb.BlogEventType_UPDATED, data)
+
+	return &blogpb.RestoreBlogRevisionResponse{
+		Blog: dataToBlogPb(data),
+	}, nil
+}
+
+func revisionToPb(rev *revisionItem) (*blogpb.BlogRevision, error) {
+	archiveTime, err := ptypes.TimestampProto(rev.ArchiveTime)
+	if err != nil {
+		return nil, err
+	}
+	return &blogpb.BlogRevision{
+		BlogId:      rev.BlogID.Hex(),
+		RevisionId:  rev.Blog.Version,
+		Blog:        dataToBlogPb(&rev.Blog),
+		ArchiveTime: archiveTime,
+		Editor:      rev.Editor,
+	}, nil
+}
@@ -0,0 +1,278 @@
+package blogserver
+
+import (
+	"context"
+	"github.com/naraycitra/grpc-go-adventure/internal/auth"
+	"github.com/naraycitra/grpc-go-adventure/internal/blog/blogpb"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"io"
+	"net"
+	"testing"
+)
+
+// subjectHeader is the metadata key the test server takes the principal from,
+// in place of a bearer token
+const subjectHeader = "test-subject"
+
+// newTestClient serves a BlogService backed by the memory store and returns a
+// client of it. The calls made with asUser are authenticated as that user.
+func newTestClient(t *testing.T) blogpb.BlogServiceClient {
+	t.Helper()
+	return serveTest(t, newTestServer())
+}
+
+func newTestServer() *server {
+	return &server{store: newMemoryStore(), events: newEventBus(16)}
+}
+
+// serveTest serves srv like newTestClient does
+func serveTest(t *testing.T, srv *server) blogpb.BlogServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			return handler(testPrincipal(ctx), req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return handler(srv, &testStream{ServerStream: ss, ctx: testPrincipal(ss.Context())})
+		}),
+	)
+	blogpb.RegisterBlogServiceServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return blogpb.NewBlogServiceClient(conn)
+}
+
+func testPrincipal(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if subject := md.Get(subjectHeader); len(subject) > 0 {
+		return auth.NewContext(ctx, &auth.Principal{Subject: subject[0]})
+	}
+	return ctx
+}
+
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context {
+	return s.ctx
+}
+
+// asUser returns a context authenticating the calls as subject
+func asUser(subject string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), subjectHeader, subject)
+}
+
+func createTestBlog(t *testing.T, client blogpb.BlogServiceClient, ctx context.Context, title string) *blogpb.Blog {
+	t.Helper()
+	res, err := client.CreateBlog(ctx, &blogpb.CreateBlogRequest{Blog: &blogpb.Blog{AuthorId: "alice", Title: title, Content: title + " content"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.GetBlog()
+}
+
+func TestRevisionsOfTrashedBlogsAreHidden(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	blog := createTestBlog(t, client, ctx, "first")
+	blog.Title = "second"
+	if _, err := client.UpdateBlog(ctx, &blogpb.UpdateBlogRequest{Blog: blog}); err != nil {
+		t.Fatal(err)
+	}
+
+	listRevisions := func() error {
+		stream, err := client.ListBlogRevisions(ctx, &blogpb.ListBlogRevisionsRequest{BlogId: blog.GetId()})
+		if err != nil {
+			return err
+		}
+		for {
+			if _, err := stream.Recv(); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+	getRevision := func() error {
+		_, err := client.GetBlogRevision(ctx, &blogpb.GetBlogRevisionRequest{BlogId: blog.GetId(), RevisionId: 1})
+		return err
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"ListBlogRevisions", listRevisions},
+		{"GetBlogRevision", getRevision},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err != nil {
+				t.Fatalf("%s() of a live blog error = %v", tt.name, err)
+			}
+		})
+	}
+
+	if _, err := client.DeleteBlog(ctx, &blogpb.DeleteBlogRequest{BlogId: blog.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" trashed", func(t *testing.T) {
+			if err := tt.call(); status.Code(err) != codes.NotFound {
+				t.Errorf("%s() of a trashed blog error = %v, want NotFound", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestTrashIsPrivateToTheAuthor(t *testing.T) {
+	client := newTestClient(t)
+	alice, bob := asUser("alice"), asUser("bob")
+	trashed := createTestBlog(t, client, alice, "trashed")
+	if _, err := client.DeleteBlog(alice, &blogpb.DeleteBlogRequest{BlogId: trashed.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+
+	readTests := []struct {
+		name     string
+		ctx      context.Context
+		wantCode codes.Code
+	}{
+		{"author", alice, codes.OK},
+		{"other author", bob, codes.PermissionDenied},
+		{"unauthenticated", context.Background(), codes.OK},
+	}
+	for _, tt := range readTests {
+		t.Run("ReadBlog "+tt.name, func(t *testing.T) {
+			_, err := client.ReadBlog(tt.ctx, &blogpb.ReadBlogRequest{BlogId: trashed.GetId(), ShowDeleted: true})
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("ReadBlog() error = %v, want %v", err, tt.wantCode)
+			}
+		})
+	}
+
+	listDeleted := func(ctx context.Context) (int, error) {
+		stream, err := client.ListDeletedBlogs(ctx, &blogpb.ListDeletedBlogsRequest{})
+		if err != nil {
+			return 0, err
+		}
+		count := 0
+		for {
+			if _, err := stream.Recv(); err != nil {
+				if err == io.EOF {
+					return count, nil
+				}
+				return count, err
+			}
+			count++
+		}
+	}
+	listTests := []struct {
+		name string
+		ctx  context.Context
+		want int
+	}{
+		{"author", alice, 1},
+		{"other author", bob, 0},
+		{"unauthenticated", context.Background(), 1},
+	}
+	for _, tt := range listTests {
+		t.Run("ListDeletedBlogs "+tt.name, func(t *testing.T) {
+			count, err := listDeleted(tt.ctx)
+			if err != nil || count != tt.want {
+				t.Errorf("ListDeletedBlogs() = %d blogs, %v, want %d", count, err, tt.want)
+			}
+		})
+	}
+
+	showDeletedTests := []struct {
+		name     string
+		ctx      context.Context
+		authorID string
+		wantCode codes.Code
+	}{
+		{"own blogs", alice, "alice", codes.OK},
+		{"blogs of another author", bob, "alice", codes.PermissionDenied},
+		{"blogs of every author", bob, "", codes.PermissionDenied},
+		{"unauthenticated", context.Background(), "", codes.OK},
+	}
+	for _, tt := range showDeletedTests {
+		t.Run("ListBlog "+tt.name, func(t *testing.T) {
+			stream, err := client.ListBlog(tt.ctx, &blogpb.ListBlogRequest{AuthorId: tt.authorID, ShowDeleted: true})
+			if err == nil {
+				for err == nil {
+					_, err = stream.Recv()
+				}
+				if err == io.EOF {
+					err = nil
+				}
+			}
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("ListBlog() error = %v, want %v", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestUpdateBlogKeepsTheAuthor(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		authorID   string
+		paths      []string
+		wantCode   codes.Code
+		wantAuthor string
+	}{
+		{"empty author in a full update", asUser("alice"), "", nil, codes.OK, "alice"},
+		{"empty author with the wildcard mask", asUser("alice"), "", []string{"*"}, codes.OK, "alice"},
+		{"empty author in the mask", asUser("alice"), "", []string{"author_id", "title"}, codes.OK, "alice"},
+		{"same author", asUser("alice"), "alice", nil, codes.OK, "alice"},
+		{"author left out of the mask", asUser("alice"), "bob", []string{"title"}, codes.OK, "alice"},
+		{"giving the blog away", asUser("alice"), "bob", nil, codes.PermissionDenied, "alice"},
+		{"giving the blog away in the mask", asUser("alice"), "bob", []string{"author_id"}, codes.PermissionDenied, "alice"},
+		{"taking the blog", asUser("bob"), "bob", nil, codes.PermissionDenied, "alice"},
+		// without authentication the author is whatever the client sends
+		{"unauthenticated", context.Background(), "bob", nil, codes.OK, "bob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t)
+			blog := createTestBlog(t, client, asUser("alice"), "title")
+
+			req := &blogpb.UpdateBlogRequest{Blog: &blogpb.Blog{Id: blog.GetId(), AuthorId: tt.authorID, Title: "new title"}}
+			if tt.paths != nil {
+				req.UpdateMask = &field_mask.FieldMask{Paths: tt.paths}
+			}
+			_, err := client.UpdateBlog(tt.ctx, req)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("UpdateBlog() error = %v, want %v", err, tt.wantCode)
+			}
+
+			res, err := client.ReadBlog(context.Background(), &blogpb.ReadBlogRequest{BlogId: blog.GetId()})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := res.GetBlog().GetAuthorId(); got != tt.wantAuthor {
+				t.Errorf("author after UpdateBlog() = %q, want %q", got, tt.wantAuthor)
+			}
+		})
+	}
+}
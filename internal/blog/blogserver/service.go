@@ -0,0 +1,146 @@
+package blogserver
+
+import (
+	"context"
+	"fmt"
+	"github.com/naraycitra/grpc-go-adventure/internal/blog/blogpb"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"time"
+)
+
+// Config configures the BlogService
+type Config struct {
+	// Store is the blog store backend: mongo or memory
+	Store    string `yaml:"store"`
+	MongoURI string `yaml:"mongo_uri"`
+	// TrashRetention is how long deleted blogs stay in the trash before being purged
+	TrashRetention time.Duration `yaml:"trash_retention"`
+	// PurgeInterval is how often the trash is checked for blogs to purge
+	PurgeInterval time.Duration `yaml:"purge_interval"`
+	// WatchReplay is how many blog events are kept for WatchBlogs clients to resume from
+	WatchReplay int `yaml:"watch_replay"`
+}
+
+// DefaultConfig returns the configuration used for the settings left unset
+func DefaultConfig() Config {
+	return Config{
+		Store:          "mongo",
+		MongoURI:       "mongodb://localhost:27017",
+		TrashRetention: 30 * 24 * time.Hour,
+		PurgeInterval:  time.Hour,
+		WatchReplay:    1024,
+	}
+}
+
+// Validate returns an error when a setting would stop the service from working
+func (c *Config) Validate() error {
+	switch c.Store {
+	case "memory":
+	case "mongo":
+		if c.MongoURI == "" {
+			return fmt.Errorf("the mongo blog store needs a mongo URI")
+		}
+	default:
+		return fmt.Errorf("unknown blog store: %s", c.Store)
+	}
+	if c.PurgeInterval <= 0 {
+		return fmt.Errorf("purge interval must be positive")
+	}
+	if c.TrashRetention < 0 {
+		return fmt.Errorf("trash retention cannot be negative")
+	}
+	if c.WatchReplay < 0 {
+		return fmt.Errorf("watch replay cannot be negative")
+	}
+	return nil
+}
+
+// Service is a running BlogService with its store and background jobs. It is
+// a prometheus.Collector exporting how long the store operations take.
+type Service struct {
+	server *server
+	// client is the MongoDB connection of the store, nil for the memory store
+	client        *mongo.Client
+	stopPurger    context.CancelFunc
+	storeDuration *prometheus.HistogramVec
+}
+
+// New opens the blog store described by cfg and starts purging the trash.
+// Close releases what it opened.
+func New(cfg Config, logger *zap.SugaredLogger) (*Service, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	sugar = logger
+
+	svc := &Service{}
+	var store BlogStore
+	switch cfg.Store {
+	case "memory":
+		sugar.Info("Using in-memory blog store")
+		store = newMemoryStore()
+	case "mongo":
+		sugar.Info("Connecting to mongodb...")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoURI))
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed connect to database: %v", err)
+		}
+		svc.client = client
+		// the indexes are created by the first successful health check or
+		// search, so the server starts while the database is down
+		store = newMongoStore(client.Database("mydb"))
+	default:
+		return nil, fmt.Errorf("unknown blog store: %s", cfg.Store)
+	}
+
+	svc.storeDuration = newStoreDuration()
+	store = newMetricsStore(store, cfg.Store, svc.storeDuration)
+	svc.server = &server{store: store, events: newEventBus(cfg.WatchReplay)}
+	purgeCtx, stopPurger := context.WithCancel(context.Background())
+	svc.stopPurger = stopPurger
+	go runPurger(purgeCtx, store, cfg.TrashRetention, cfg.PurgeInterval)
+	return svc, nil
+}
+
+// Register adds the BlogService to a gRPC server
+func (svc *Service) Register(s *grpc.Server) {
+	blogpb.RegisterBlogServiceServer(s, svc.server)
+}
+
+// Check returns an error when the service cannot serve requests because its store is unavailable
+func (svc *Service) Check(ctx context.Context) error {
+	return svc.server.store.Ping(ctx)
+}
+
+// Describe implements prometheus.Collector
+func (svc *Service) Describe(ch chan<- *prometheus.Desc) {
+	svc.storeDuration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (svc *Service) Collect(ch chan<- prometheus.Metric) {
+	svc.storeDuration.Collect(ch)
+}
+
+// StopWatchers ends the WatchBlogs streams with UNAVAILABLE and refuses the new
+// ones, so that a graceful stop of the gRPC server does not wait for these
+// never-ending calls.
+func (svc *Service) StopWatchers() {
+	svc.server.events.close()
+}
+
+// Close stops the background jobs and disconnects from the database. Call it
+// once the gRPC server no longer serves requests.
+func (svc *Service) Close() {
+	svc.stopPurger()
+	if svc.client != nil {
+		sugar.Info("Disconnecting from mongodb")
+		svc.client.Disconnect(context.TODO())
+	}
+}
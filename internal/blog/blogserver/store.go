@@ -0,0 +1,208 @@
+package blogserver
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"strings"
+	"time"
+)
+
+var (
+	// errNotFound is returned by a BlogStore when the blog does not exist
+	errNotFound = errors.New("blog not found")
+	// errInvalidID is returned by a BlogStore when the blog id cannot be parsed
+	errInvalidID = errors.New("invalid blog id")
+	// errRevisionNotFound is returned by a BlogStore when the blog has no such revision
+	errRevisionNotFound = errors.New("blog revision not found")
+	// errNotDeleted is returned by a BlogStore when undeleting a blog that is not in the trash
+	errNotDeleted = errors.New("blog is not deleted")
+	// errConflict is returned by a BlogStore when the stored version differs from the expected one
+	errConflict = errors.New("blog was modified concurrently")
+	// errAlreadyExists is returned by a BlogStore when creating a blog with an id that is taken
+	errAlreadyExists = errors.New("blog already exists")
+	// errPartiallyCreated is returned by BlogStore.CreateMany when some blogs with
+	// ids chosen by the client may have been stored despite the failure
+	errPartiallyCreated = errors.New("some blogs may have been created")
+)
+
+// anyVersion makes BlogStore.Delete skip the version check
+const anyVersion int64 = -1
+
+// BlogStore is the persistence layer used by the BlogService handlers
+type BlogStore interface {
+	// Create stores a new blog and returns it with its generated id. A blog with item.ID
+	// already set keeps it, or errAlreadyExists is returned when the id is taken.
+	Create(ctx context.Context, item *blogItem) (*blogItem, error)
+	// CreateMany stores all the blogs or none of them and returns them with their ids,
+	// which are generated or kept like Create does. When that cannot be guaranteed
+	// the error wraps errPartiallyCreated.
+	CreateMany(ctx context.Context, items []*blogItem) ([]*blogItem, error)
+	// Read returns the blog with the given id or errNotFound
+	Read(ctx context.Context, id string) (*blogItem, error)
+	// Update replaces the blog identified by item.ID if its stored version still equals
+	// item.Version and bumps the version, or returns errNotFound (also for trashed blogs)
+	// or errConflict.
+	// The replaced version is archived as a revision edited by editor.
+	Update(ctx context.Context, item *blogItem, editor string) (*blogItem, error)
+	// Delete moves the blog with the given id to the trash if its stored version equals version
+	// (or version is anyVersion) and returns the trashed blog, or returns errNotFound or
+	// errConflict. Trashed blogs are still returned by Read and count as not found for a
+	// second Delete.
+	Delete(ctx context.Context, id string, version int64) (*blogItem, error)
+	// Undelete takes a blog out of the trash or returns errNotFound or errNotDeleted
+	Undelete(ctx context.Context, id string) (*blogItem, error)
+	// Purge permanently removes the blogs trashed before the given time together with
+	// their revisions and returns how many blogs were removed
+	Purge(ctx context.Context, before time.Time) (int, error)
+	// List calls fn for every blog matching opts in id order, stopping at the first error
+	List(ctx context.Context, opts listOptions, fn func(*blogItem) error) error
+	// Search calls fn for at most limit live blogs whose title or content contains
+	// any of the words of query, best match first
+	Search(ctx context.Context, query string, limit int, fn func(*searchHit) error) error
+	// ListRevisions calls fn for every archived revision of a blog, newest first
+	ListRevisions(ctx context.Context, id string, fn func(*revisionItem) error) error
+	// ReadRevision returns the revision of a blog archived at the given version or errRevisionNotFound
+	ReadRevision(ctx context.Context, id string, version int64) (*revisionItem, error)
+	// Ping returns an error when the store cannot serve requests
+	Ping(ctx context.Context) error
+}
+
+// listOptions narrows down and orders the blogs returned by BlogStore.List
+type listOptions struct {
+	// AuthorID only matches blogs of this author when not empty
+	AuthorID string
+	// TitlePrefix only matches blogs whose title starts with it when not empty
+	TitlePrefix string
+	// OrderBy is the key blogs are listed by, ties are broken by id
+	OrderBy orderBy
+	// Descending lists the newest blogs first
+	Descending bool
+	// After skips every blog up to and including this one in the listing order.
+	// Only its id and OrderBy key are used.
+	After *blogItem
+	// Limit caps the number of listed blogs, 0 means no limit
+	Limit int
+	// Trash tells whether trashed blogs are listed
+	Trash trashFilter
+}
+
+type orderBy int
+
+const (
+	// orderByID lists blogs by id, which is their creation order
+	orderByID orderBy = iota
+	// orderByCreateTime lists blogs by create time
+	orderByCreateTime
+	// orderByUpdateTime lists blogs by last update time
+	orderByUpdateTime
+)
+
+// field is the bson field of blogItem the order is based on
+func (o orderBy) field() string {
+	switch o {
+	case orderByCreateTime:
+		return "create_time"
+	case orderByUpdateTime:
+		return "update_time"
+	default:
+		return "_id"
+	}
+}
+
+type trashFilter int
+
+const (
+	// trashExclude lists live blogs only
+	trashExclude trashFilter = iota
+	// trashInclude lists live and trashed blogs
+	trashInclude
+	// trashOnly lists trashed blogs only
+	trashOnly
+)
+
+// match reports whether the blog passes the AuthorID, TitlePrefix, Trash and After filters
+func (o listOptions) match(item *blogItem) bool {
+	if o.Trash == trashExclude && item.deleted() || o.Trash == trashOnly && !item.deleted() {
+		return false
+	}
+	if o.AuthorID != "" && item.AuthorID != o.AuthorID {
+		return false
+	}
+	if !strings.HasPrefix(item.Title, o.TitlePrefix) {
+		return false
+	}
+	return o.After == nil || o.less(o.After, item)
+}
+
+// less reports whether a is listed before b
+func (o listOptions) less(a, b *blogItem) bool {
+	ka, kb := a.orderKey(o.OrderBy), b.orderKey(o.OrderBy)
+	if !ka.Equal(kb) {
+		if o.Descending {
+			return ka.After(kb)
+		}
+		return ka.Before(kb)
+	}
+	if o.Descending {
+		return a.ID.Hex() > b.ID.Hex()
+	}
+	return a.ID.Hex() < b.ID.Hex()
+}
+
+type blogItem struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty"`
+	AuthorID string             `bson:"author_id"`
+	Title    string             `bson:"title"`
+	Content  string             `bson:"content"`
+	// Version is incremented by every update, blogs written before versioning have 0
+	Version int64 `bson:"version"`
+	// DeleteTime is set while the blog is in the trash
+	DeleteTime *time.Time `bson:"delete_time,omitempty"`
+	// CreateTime and UpdateTime are managed by the server, blogs written before
+	// they existed have zero times
+	CreateTime time.Time `bson:"create_time"`
+	UpdateTime time.Time `bson:"update_time"`
+}
+
+// orderKey returns the time the blog is ordered by, orderByID has none
+func (item *blogItem) orderKey(o orderBy) time.Time {
+	switch o {
+	case orderByCreateTime:
+		return item.CreateTime
+	case orderByUpdateTime:
+		return item.UpdateTime
+	default:
+		return time.Time{}
+	}
+}
+
+func (item *blogItem) deleted() bool {
+	return item.DeleteTime != nil
+}
+
+// revisionItem is a past version of a blog, archived when an update replaced it
+type revisionItem struct {
+	BlogID primitive.ObjectID `bson:"blog_id"`
+	// Blog is the content of the blog as it was, Blog.Version identifies the revision
+	Blog        blogItem  `bson:"blog"`
+	Editor      string    `bson:"editor"`
+	ArchiveTime time.Time `bson:"archive_time"`
+}
+
+func newRevisionItem(old blogItem, editor string) revisionItem {
+	return revisionItem{
+		BlogID:      old.ID,
+		Blog:        old,
+		Editor:      editor,
+		ArchiveTime: time.Now().UTC(),
+	}
+}
+
+func parseID(id string) (primitive.ObjectID, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, errInvalidID
+	}
+	return oid, nil
+}
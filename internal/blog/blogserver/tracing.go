@@ -0,0 +1,33 @@
+package blogserver
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/naraycitra/grpc-go-adventure/internal/blog/blogserver")
+
+// traced runs call, which performs operation op on coll, in a child span of ctx.
+// A missing document is an answer, not a failure of the span.
+func traced(ctx context.Context, coll *mongo.Collection, op string, call func(ctx context.Context) error) error {
+	ctx, span := tracer.Start(ctx, coll.Name()+"."+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemMongoDB,
+			semconv.DBNameKey.String(coll.Database().Name()),
+			semconv.DBMongoDBCollectionKey.String(coll.Name()),
+			semconv.DBOperationKey.String(op),
+		))
+	defer span.End()
+
+	err := call(ctx)
+	if err != nil && err != mongo.ErrNoDocuments {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,54 @@
+package blogserver
+
+import (
+	"fmt"
+	"github.com/naraycitra/grpc-go-adventure/internal/blog/blogpb"
+	"google.golang.org/genproto/protobuf/field_mask"
+)
+
+// blogFieldSetters maps every update_mask path of a Blog to the function copying that field
+var blogFieldSetters = map[string]func(data *blogItem, blog *blogpb.Blog){
+	"author_id": func(data *blogItem, blog *blogpb.Blog) { data.AuthorID = blog.GetAuthorId() },
+	"title":     func(data *blogItem, blog *blogpb.Blog) { data.Title = blog.GetTitle() },
+	"content":   func(data *blogItem, blog *blogpb.Blog) { data.Content = blog.GetContent() },
+}
+
+// blogOutputOnlyPaths are the Blog fields set by the server, they are accepted in an
+// update_mask but never copied from the request
+var blogOutputOnlyPaths = map[string]bool{
+	"id":          true,
+	"etag":        true,
+	"delete_time": true,
+	"create_time": true,
+	"update_time": true,
+}
+
+// applyUpdateMask copies the fields of blog listed in mask into data following AIP-134:
+// an empty mask or "*" updates every field, output only fields are ignored and any
+// other unknown path is an error.
+// Nothing is copied when the mask is invalid.
+func applyUpdateMask(data *blogItem, blog *blogpb.Blog, mask *field_mask.FieldMask) error {
+	paths := mask.GetPaths()
+	if len(paths) == 0 || len(paths) == 1 && paths[0] == "*" {
+		for _, set := range blogFieldSetters {
+			set(data, blog)
+		}
+		return nil
+	}
+
+	setters := make([]func(*blogItem, *blogpb.Blog), 0, len(paths))
+	for _, path := range paths {
+		if blogOutputOnlyPaths[path] {
+			continue
+		}
+		set, ok := blogFieldSetters[path]
+		if !ok {
+			return fmt.Errorf("unknown update_mask path %q", path)
+		}
+		setters = append(setters, set)
+	}
+	for _, set := range setters {
+		set(data, blog)
+	}
+	return nil
+}
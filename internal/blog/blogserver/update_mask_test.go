@@ -0,0 +1,43 @@
+package blogserver
+
+import (
+	"github.com/naraycitra/grpc-go-adventure/internal/blog/blogpb"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"testing"
+)
+
+func TestApplyUpdateMask(t *testing.T) {
+	stored := blogItem{AuthorID: "alice", Title: "old title", Content: "old content"}
+	update := &blogpb.Blog{Id: "ignored", AuthorId: "bob", Title: "new title", Content: "new content"}
+
+	tests := []struct {
+		name    string
+		paths   []string
+		want    blogItem
+		wantErr bool
+	}{
+		{"empty mask updates every field", nil, blogItem{AuthorID: "bob", Title: "new title", Content: "new content"}, false},
+		{"wildcard updates every field", []string{"*"}, blogItem{AuthorID: "bob", Title: "new title", Content: "new content"}, false},
+		{"single field", []string{"title"}, blogItem{AuthorID: "alice", Title: "new title", Content: "old content"}, false},
+		{"several fields", []string{"title", "content"}, blogItem{AuthorID: "alice", Title: "new title", Content: "new content"}, false},
+		{"output only fields are ignored", []string{"id", "etag", "update_time", "content"}, blogItem{AuthorID: "alice", Title: "old title", Content: "new content"}, false},
+		{"unknown path", []string{"title", "tags"}, stored, true},
+		{"wildcard with other paths", []string{"*", "title"}, stored, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := stored
+			var mask *field_mask.FieldMask
+			if tt.paths != nil {
+				mask = &field_mask.FieldMask{Paths: tt.paths}
+			}
+			err := applyUpdateMask(&data, update, mask)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyUpdateMask() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if data != tt.want {
+				t.Errorf("applyUpdateMask() = %+v, want %+v", data, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Package calculatorclient connects to the CalculatorService with its service config.
+package calculatorclient
+
+import (
+	"github.com/naraycitra/grpc-go-adventure/internal/calculator/calculatorpb"
+	"github.com/naraycitra/grpc-go-adventure/internal/grpcclient"
+	"google.golang.org/grpc"
+)
+
+// ServiceConfig retries every call that found the server unavailable, the
+// computations have no side effect. SquareRoot is hedged instead: a second
+// attempt is sent when the first one takes long, and the first response wins.
+const ServiceConfig = `{
+  "methodConfig": [
+    {
+      "name": [{"service": "calculatorpb.CalculatorService"}],
+      "retryPolicy": {
+        "maxAttempts": 3,
+        "initialBackoff": "0.1s",
+        "maxBackoff": "1s",
+        "backoffMultiplier": 2,
+        "retryableStatusCodes": ["UNAVAILABLE"]
+      }
+    },
+    {
+      "name": [{"service": "calculatorpb.CalculatorService", "method": "Calculate"}],
+      "timeout": "2s",
+      "retryPolicy": {
+        "maxAttempts": 3,
+        "initialBackoff": "0.1s",
+        "maxBackoff": "1s",
+        "backoffMultiplier": 2,
+        "retryableStatusCodes": ["UNAVAILABLE"]
+      }
+    },
+    {
+      "name": [{"service": "calculatorpb.CalculatorService", "method": "SquareRoot"}],
+      "timeout": "2s",
+      "hedgingPolicy": {
+        "maxAttempts": 3,
+        "hedgingDelay": "0.05s",
+        "nonFatalStatusCodes": ["UNAVAILABLE"]
+      }
+    }
+  ]
+}`
+
+// Client is a CalculatorService client owning its connection
+type Client struct {
+	calculatorpb.CalculatorServiceClient
+	conn *grpc.ClientConn
+}
+
+// Dial connects to the CalculatorService of cfg.Addr
+func Dial(cfg grpcclient.Config) (*Client, error) {
+	conn, err := grpcclient.Dial(cfg, ServiceConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{CalculatorServiceClient: calculatorpb.NewCalculatorServiceClient(conn), conn: conn}, nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
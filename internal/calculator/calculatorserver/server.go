@@ -1,23 +1,27 @@
-package main
+// Package calculatorserver implements the CalculatorService.
+package calculatorserver
 
 import (
 	"context"
 	"fmt"
 	"github.com/naraycitra/grpc-go-adventure/internal/calculator/calculatorpb"
-	"google.golang.org/grpc"
+	"github.com/naraycitra/grpc-go-adventure/internal/logging"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"io"
-	"log"
 	"math"
-	"net"
 )
 
 type server struct {
 }
 
+// New returns the CalculatorService implementation
+func New() calculatorpb.CalculatorServiceServer {
+	return &server{}
+}
+
 func (*server) Calculate(ctx context.Context, req *calculatorpb.CalculatorRequest) (*calculatorpb.CalculatorResponse, error) {
-	fmt.Printf("Calculate Service invoked with: %v", req)
+	logging.FromContext(ctx).Infof("Calculate Service invoked with: %v", req)
 	x := req.GetCalculating().GetX()
 	y := req.GetCalculating().GetY()
 	sum := x + y
@@ -28,7 +32,8 @@ func (*server) Calculate(ctx context.Context, req *calculatorpb.CalculatorReques
 }
 
 func (*server) PrimeNumberDecomposition(req *calculatorpb.PrimeNumberDecompositionRequest, stream calculatorpb.CalculatorService_PrimeNumberDecompositionServer) error {
-	fmt.Printf("PrimeNumberDecomposition Service invoked with: %v", req)
+	logger := logging.FromContext(stream.Context())
+	logger.Infof("PrimeNumberDecomposition Service invoked with: %v", req)
 	var k int64
 	k = 2
 	n := req.GetNumber()
@@ -37,31 +42,35 @@ func (*server) PrimeNumberDecomposition(req *calculatorpb.PrimeNumberDecompositi
 			res := &calculatorpb.PrimeNumberDecompositionResponse{
 				Result: k,
 			}
-			stream.Send(res)
+			if err := stream.Send(res); err != nil {
+				return err
+			}
 			n /= k
 		} else {
 			k++
-			fmt.Printf("Divisor has incrased by:%v", k)
+			logger.Debugf("Divisor has incrased by:%v", k)
 		}
 	}
 	return nil
 }
 
 func (*server) ComputeAverage(stream calculatorpb.CalculatorService_ComputeAverageServer) error {
-	val := &stream
-	fmt.Printf("ComputeAverage service was invoked with %v\n", *val)
+	logging.FromContext(stream.Context()).Info("ComputeAverage service was invoked")
 	var result float64
 	i := 0
 	for {
 		req, err := stream.Recv()
 		if err == io.EOF {
+			if i == 0 {
+				return status.Error(codes.InvalidArgument, "Received no number to average")
+			}
 			result /= float64(i)
 			return stream.SendAndClose(&calculatorpb.ComputeAverageResponse{
 				Result: result,
 			})
 		}
 		if err != nil {
-			log.Fatalf("Error received stream from client: %v", err)
+			return status.Errorf(status.Code(err), "error receiving the stream from the client: %v", status.Convert(err).Message())
 		}
 		result += float64(req.GetNumber())
 		i++
@@ -69,8 +78,7 @@ func (*server) ComputeAverage(stream calculatorpb.CalculatorService_ComputeAvera
 }
 
 func (*server) FindMaximum(stream calculatorpb.CalculatorService_FindMaximumServer) error {
-	val := &stream
-	fmt.Printf("ComputeAverage service was invoked with %v\n", *val)
+	logging.FromContext(stream.Context()).Info("FindMaximum service was invoked")
 	var result int64
 	for {
 		req, err := stream.Recv()
@@ -78,8 +86,7 @@ func (*server) FindMaximum(stream calculatorpb.CalculatorService_FindMaximumServ
 			return nil
 		}
 		if err != nil {
-			log.Fatalf("Error received stream from client: %v", err)
-			return err
+			return status.Errorf(status.Code(err), "error receiving the stream from the client: %v", status.Convert(err).Message())
 		}
 		n := req.GetNumber()
 		if n > result {
@@ -90,8 +97,7 @@ func (*server) FindMaximum(stream calculatorpb.CalculatorService_FindMaximumServ
 		}
 		err = stream.Send(res)
 		if err != nil {
-			log.Fatalf("Error while send stream to client: %v", err)
-			return err
+			return status.Errorf(status.Code(err), "error sending the stream to the client: %v", status.Convert(err).Message())
 		}
 	}
 }
@@ -105,21 +111,3 @@ func (*server) SquareRoot(ctx context.Context, req *calculatorpb.SquareRootReque
 		Root: math.Sqrt(float64(number)),
 	}, nil
 }
-
-func main() {
-	fmt.Println("Calcutator Server starting...")
-
-	lis, err := net.Listen("tcp", "0.0.0.0:50051")
-	if err != nil {
-		log.Fatalf("Failed to listen: %v", err)
-	}
-
-	s := grpc.NewServer()
-
-	calculatorpb.RegisterCalculatorServiceServer(s, &server{})
-
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("Failed to serve:%v", err)
-	}
-
-}
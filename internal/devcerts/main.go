@@ -0,0 +1,165 @@
+// Command devcerts writes a development CA and the server and client certificates
+// it signs, to try TLS and mutual TLS locally. Running it again renews the server
+// and client certificates with the existing CA, which servers pick up without a restart.
+//
+//	devcerts [-out dir] [-hosts localhost,127.0.0.1] [-client-cn name] [-days n]
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func main() {
+	out := flag.String("out", "certs", "directory the PEM files are written to")
+	hosts := flag.String("hosts", "localhost,127.0.0.1,::1", "comma separated host names and IPs of the server certificate")
+	clientCN := flag.String("client-cn", "dev-client", "common name of the client certificate")
+	days := flag.Int("days", 365, "validity of the server and client certificates")
+	flag.Parse()
+
+	if err := run(*out, strings.Split(*hosts, ","), *clientCN, time.Duration(*days)*24*time.Hour); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(out string, hosts []string, clientCN string, validity time.Duration) error {
+	if err := os.MkdirAll(out, 0755); err != nil {
+		return err
+	}
+
+	caCert, caKey, err := loadCA(out)
+	if os.IsNotExist(err) {
+		caCert, caKey, err = createCA(out)
+		if err == nil {
+			fmt.Printf("Created the CA in %s\n", filepath.Join(out, "ca.pem"))
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	server := &x509.Certificate{
+		Subject: pkix.Name{CommonName: hosts[0]},
+		// client authentication lets the server dial itself under mutual TLS
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	for _, h := range hosts {
+		if h = strings.TrimSpace(h); h == "" {
+			continue
+		}
+		if ip := net.ParseIP(h); ip != nil {
+			server.IPAddresses = append(server.IPAddresses, ip)
+		} else {
+			server.DNSNames = append(server.DNSNames, h)
+		}
+	}
+	if err := issue(out, "server", server, validity, caCert, caKey); err != nil {
+		return err
+	}
+
+	client := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: clientCN},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	return issue(out, "client", client, validity, caCert, caKey)
+}
+
+// loadCA reads the CA written by a previous run
+func loadCA(out string) (*x509.Certificate, crypto.Signer, error) {
+	pair, err := tls.LoadX509KeyPair(filepath.Join(out, "ca.pem"), filepath.Join(out, "ca-key.pem"))
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("the CA key cannot sign")
+	}
+	return cert, key, nil
+}
+
+func createCA(out string) (*x509.Certificate, crypto.Signer, error) {
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "grpc-go-adventure dev CA"},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	key, der, err := sign(template, 10*365*24*time.Hour, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := write(out, "ca", der, key); err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	return cert, key, err
+}
+
+// issue signs template with the CA and writes name.pem and name-key.pem
+func issue(out, name string, template *x509.Certificate, validity time.Duration, caCert *x509.Certificate, caKey crypto.Signer) error {
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	key, der, err := sign(template, validity, caCert, caKey)
+	if err != nil {
+		return err
+	}
+	if err := write(out, name, der, key); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote the %s certificate to %s\n", name, filepath.Join(out, name+".pem"))
+	return nil
+}
+
+// sign generates a key for template and signs it with parentKey, or self-signs
+// it when parent is nil
+func sign(template *x509.Certificate, validity time.Duration, parent *x509.Certificate, parentKey crypto.Signer) (*ecdsa.PrivateKey, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	template.SerialNumber = serial
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(validity)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	return key, der, err
+}
+
+// write saves the certificate and its key, the key readable by the owner only
+func write(out, name string, der []byte, key *ecdsa.PrivateKey) error {
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := ioutil.WriteFile(filepath.Join(out, name+"-key.pem"), keyPEM, 0600); err != nil {
+		return err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return ioutil.WriteFile(filepath.Join(out, name+".pem"), certPEM, 0644)
+}
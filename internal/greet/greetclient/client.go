@@ -0,0 +1,56 @@
+// Package greetclient connects to the GreetService with its service config.
+package greetclient
+
+import (
+	"github.com/naraycitra/grpc-go-adventure/internal/greet/greetpb"
+	"github.com/naraycitra/grpc-go-adventure/internal/grpcclient"
+	"google.golang.org/grpc"
+)
+
+// ServiceConfig retries every call that found the server unavailable, greetings
+// have no side effect. GreetWithDeadLine has no timeout, its callers set the deadline.
+const ServiceConfig = `{
+  "methodConfig": [
+    {
+      "name": [{"service": "greet.GreetService"}],
+      "retryPolicy": {
+        "maxAttempts": 3,
+        "initialBackoff": "0.1s",
+        "maxBackoff": "1s",
+        "backoffMultiplier": 2,
+        "retryableStatusCodes": ["UNAVAILABLE"]
+      }
+    },
+    {
+      "name": [{"service": "greet.GreetService", "method": "Greet"}],
+      "timeout": "5s",
+      "retryPolicy": {
+        "maxAttempts": 3,
+        "initialBackoff": "0.1s",
+        "maxBackoff": "1s",
+        "backoffMultiplier": 2,
+        "retryableStatusCodes": ["UNAVAILABLE"]
+      }
+    }
+  ]
+}`
+
+// Client is a GreetService client owning its connection
+type Client struct {
+	greetpb.GreetServiceClient
+	conn *grpc.ClientConn
+}
+
+// Dial connects to the GreetService of cfg.Addr
+func Dial(cfg grpcclient.Config) (*Client, error) {
+	conn, err := grpcclient.Dial(cfg, ServiceConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{GreetServiceClient: greetpb.NewGreetServiceClient(conn), conn: conn}, nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -1,23 +1,27 @@
-package main
+// Package greetserver implements the GreetService.
+package greetserver
 
 import (
 	"context"
 	"fmt"
 	"github.com/naraycitra/grpc-go-adventure/internal/greet/greetpb"
-	"google.golang.org/grpc"
+	"github.com/naraycitra/grpc-go-adventure/internal/logging"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"io"
-	"log"
-	"net"
 	"time"
 )
 
 type server struct {
 }
 
+// New returns the GreetService implementation
+func New() greetpb.GreetServiceServer {
+	return &server{}
+}
+
 func (*server) Greet(ctx context.Context, req *greetpb.GreetRequest) (*greetpb.GreetResponse, error) {
-	fmt.Printf("Greet service was invoked with %v\n", req)
+	logging.FromContext(ctx).Infof("Greet service was invoked with %v", req)
 	fn := req.GetGreeting().GetFirstName()
 	ln := req.GetGreeting().GetLastName()
 	result := "Hello, " + fn + ln
@@ -28,7 +32,7 @@ func (*server) Greet(ctx context.Context, req *greetpb.GreetRequest) (*greetpb.G
 }
 
 func (*server) GreetManyTimes(req *greetpb.GreetManyTimesRequest, stream greetpb.GreetService_GreetManyTimesServer) error {
-	fmt.Printf("GreetManyTimes service was invoked with %v\n", req)
+	logging.FromContext(stream.Context()).Infof("GreetManyTimes service was invoked with %v", req)
 	fn := req.GetGreeting().GetFirstName()
 	ln := req.GetGreeting().GetLastName()
 
@@ -37,15 +41,16 @@ func (*server) GreetManyTimes(req *greetpb.GreetManyTimesRequest, stream greetpb
 		res := &greetpb.GreetManyTimesResponse{
 			Result: result,
 		}
-		stream.Send(res)
+		if err := stream.Send(res); err != nil {
+			return err
+		}
 		time.Sleep(1 * time.Second)
 	}
 	return nil
 }
 
 func (*server) LongGreet(stream greetpb.GreetService_LongGreetServer) error {
-	val := &stream
-	fmt.Printf("LongGreet service was invoked with %v\n", *val)
+	logging.FromContext(stream.Context()).Info("LongGreet service was invoked")
 	var result string
 	for {
 		req, err := stream.Recv()
@@ -55,8 +60,7 @@ func (*server) LongGreet(stream greetpb.GreetService_LongGreetServer) error {
 			})
 		}
 		if err != nil {
-			log.Fatalf("Error received stream from client: %v", err)
-			return err
+			return status.Errorf(status.Code(err), "error receiving the stream from the client: %v", status.Convert(err).Message())
 		}
 		fn := req.GetGreeting().GetFirstName()
 		ln := req.GetGreeting().GetLastName()
@@ -65,16 +69,14 @@ func (*server) LongGreet(stream greetpb.GreetService_LongGreetServer) error {
 }
 
 func (*server) GreetEveryone(stream greetpb.GreetService_GreetEveryoneServer) error {
-	val := &stream
-	fmt.Printf("LongGreet service was invoked with %v\n", *val)
+	logging.FromContext(stream.Context()).Info("GreetEveryone service was invoked")
 	for {
 		req, err := stream.Recv()
 		if err == io.EOF {
 			return nil
 		}
 		if err != nil {
-			log.Fatalf("Error received stream from client: %v", err)
-			return err
+			return status.Errorf(status.Code(err), "error receiving the stream from the client: %v", status.Convert(err).Message())
 		}
 		fn := req.GetGreeting().GetFirstName()
 		ln := req.GetGreeting().GetLastName()
@@ -84,18 +86,18 @@ func (*server) GreetEveryone(stream greetpb.GreetService_GreetEveryoneServer) er
 		}
 		err = stream.Send(res)
 		if err != nil {
-			log.Fatalf("Error while send stream to client: %v", err)
-			return err
+			return status.Errorf(status.Code(err), "error sending the stream to the client: %v", status.Convert(err).Message())
 		}
 	}
 }
 
 func (*server) GreetWithDeadLine(ctx context.Context, req *greetpb.GreetWithDeadLineRequest) (*greetpb.GreetWithDeadLineResponse, error) {
-	fmt.Printf("GreetWithDeadLine service was invoked with %v\n", req)
+	logger := logging.FromContext(ctx)
+	logger.Infof("GreetWithDeadLine service was invoked with %v", req)
 	for i := 0; i < 3; i++ {
 		if ctx.Err() == context.Canceled {
 			// the client canceled the request
-			fmt.Println("The Client cancel the request")
+			logger.Info("The Client cancel the request")
 			return nil, status.Error(codes.Canceled, "The Client canceled the request")
 		}
 		time.Sleep(1 * time.Second)
@@ -108,21 +110,3 @@ func (*server) GreetWithDeadLine(ctx context.Context, req *greetpb.GreetWithDead
 	}
 	return res, nil
 }
-
-func main() {
-	fmt.Println("Greet Server started...")
-
-	lis, err := net.Listen("tcp", "0.0.0.0:50051")
-
-	if err != nil {
-		log.Fatalf("Failed to listen:%v", err)
-	}
-
-	s := grpc.NewServer()
-	greetpb.RegisterGreetServiceServer(s, &server{})
-
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("Failed to serve:%v", err)
-	}
-
-}
@@ -0,0 +1,68 @@
+// Package grpcclient dials the gRPC services with the timeouts, retry and hedging
+// policies of their JSON service config, as described in
+// https://github.com/grpc/grpc/blob/master/doc/service_config.md.
+//
+// grpc-go applies the timeouts and the retry policies itself but ignores the
+// hedging policies, which the connections returned by Dial apply to unary calls.
+package grpcclient
+
+import (
+	"fmt"
+	"github.com/naraycitra/grpc-go-adventure/internal/auth"
+	"github.com/naraycitra/grpc-go-adventure/internal/tlsconfig"
+	"github.com/naraycitra/grpc-go-adventure/internal/tracing"
+	"google.golang.org/grpc"
+	"io/ioutil"
+)
+
+// Config configures the connection to a server
+type Config struct {
+	// Addr is the address of the server
+	Addr string
+	// Token is a bearer JWT sent with every call when set
+	Token string
+	// TLS dials with TLS when set, in plaintext otherwise
+	TLS *tlsconfig.ClientConfig
+	// ServiceConfig is a JSON service config used instead of the one of the service
+	ServiceConfig string
+}
+
+// LoadServiceConfig reads a JSON service config to set in Config.ServiceConfig
+func LoadServiceConfig(filename string) (string, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Dial connects to the server of cfg, applying cfg.ServiceConfig or else
+// serviceConfig to the calls. Like grpc.Dial it does not wait for the connection.
+func Dial(cfg Config, serviceConfig string) (*grpc.ClientConn, error) {
+	if cfg.ServiceConfig != "" {
+		serviceConfig = cfg.ServiceConfig
+	}
+	hedging, err := parseHedgingPolicies(serviceConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service config: %v", err)
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithStatsHandler(tracing.NewClientHandler()),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		grpc.WithUnaryInterceptor(hedging.UnaryClientInterceptor()),
+	}
+	if cfg.TLS != nil {
+		creds, err := tlsconfig.NewClientCredentials(*cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	if cfg.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(auth.NewTokenCredentials(cfg.Token)))
+	}
+	return grpc.Dial(cfg.Addr, opts...)
+}
@@ -0,0 +1,205 @@
+package grpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxHedgedAttempts caps maxAttempts like the gRPC implementations do
+const maxHedgedAttempts = 5
+
+// serviceConfig is the part of a JSON service config the hedging policies are read from
+type serviceConfig struct {
+	MethodConfig []struct {
+		Name []struct {
+			Service string `json:"service"`
+			Method  string `json:"method"`
+		} `json:"name"`
+		Timeout       string          `json:"timeout"`
+		RetryPolicy   json.RawMessage `json:"retryPolicy"`
+		HedgingPolicy *struct {
+			MaxAttempts         int          `json:"maxAttempts"`
+			HedgingDelay        string       `json:"hedgingDelay"`
+			NonFatalStatusCodes []codes.Code `json:"nonFatalStatusCodes"`
+		} `json:"hedgingPolicy"`
+	} `json:"methodConfig"`
+}
+
+// hedgingPolicy sends up to maxAttempts copies of a call, one every delay until
+// one of them succeeds or fails with a fatal code
+type hedgingPolicy struct {
+	maxAttempts int
+	delay       time.Duration
+	nonFatal    map[codes.Code]bool
+	// timeout bounds all the attempts together, 0 for none
+	timeout time.Duration
+}
+
+// hedgingPolicies holds the policies by method name, service name followed by
+// the method or alone for all its methods, the empty name applying to all the
+// services. A nil policy disables hedging for the methods it names.
+type hedgingPolicies map[string]*hedgingPolicy
+
+func parseHedgingPolicies(js string) (hedgingPolicies, error) {
+	var sc serviceConfig
+	if err := json.Unmarshal([]byte(js), &sc); err != nil {
+		return nil, err
+	}
+	policies := make(hedgingPolicies)
+	for _, mc := range sc.MethodConfig {
+		var policy *hedgingPolicy
+		if hp := mc.HedgingPolicy; hp != nil {
+			if mc.RetryPolicy != nil {
+				return nil, fmt.Errorf("a method config cannot have both a retry and a hedging policy")
+			}
+			if hp.MaxAttempts < 2 {
+				return nil, fmt.Errorf("hedging maxAttempts must be at least 2")
+			}
+			policy = &hedgingPolicy{maxAttempts: hp.MaxAttempts, nonFatal: make(map[codes.Code]bool)}
+			if policy.maxAttempts > maxHedgedAttempts {
+				policy.maxAttempts = maxHedgedAttempts
+			}
+			var err error
+			if policy.delay, err = parseDuration(hp.HedgingDelay); err != nil {
+				return nil, fmt.Errorf("invalid hedgingDelay: %v", err)
+			}
+			if policy.timeout, err = parseDuration(mc.Timeout); err != nil {
+				return nil, fmt.Errorf("invalid timeout: %v", err)
+			}
+			for _, code := range hp.NonFatalStatusCodes {
+				policy.nonFatal[code] = true
+			}
+		}
+		for _, name := range mc.Name {
+			if name.Service == "" && name.Method != "" {
+				return nil, fmt.Errorf("method %s has no service", name.Method)
+			}
+			key := name.Service
+			if name.Method != "" {
+				key += "/" + name.Method
+			}
+			if _, ok := policies[key]; ok {
+				return nil, fmt.Errorf("duplicate method config for %q", key)
+			}
+			policies[key] = policy
+		}
+	}
+	return policies, nil
+}
+
+// parseDuration parses the durations of service configs like "0.5s", empty
+// for none
+func parseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	seconds, err := strconv.ParseFloat(strings.TrimSuffix(s, "s"), 64)
+	if err != nil || !strings.HasSuffix(s, "s") {
+		return 0, fmt.Errorf("%q is not a number of seconds", s)
+	}
+	if seconds < 0 {
+		return 0, fmt.Errorf("%q is negative", s)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// lookup returns the policy of the fully-qualified method, the config of the
+// method taking precedence over the one of its service and the default one
+func (h hedgingPolicies) lookup(fullMethod string) *hedgingPolicy {
+	name := strings.TrimPrefix(fullMethod, "/")
+	if policy, ok := h[name]; ok {
+		return policy
+	}
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		if policy, ok := h[name[:i]]; ok {
+			return policy
+		}
+	}
+	return h[""]
+}
+
+// UnaryClientInterceptor hedges the unary calls to the methods with a hedging
+// policy. The calls made with call options are sent once, since options like
+// grpc.Header would be written by every attempt.
+func (h hedgingPolicies) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		policy := h.lookup(method)
+		msg, ok := reply.(proto.Message)
+		if policy == nil || !ok || len(opts) > 0 {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		return policy.invoke(ctx, method, req, msg, cc, invoker)
+	}
+}
+
+// invoke sends the attempts of a call and copies the response of the first
+// successful one to reply
+func (p *hedgingPolicy) invoke(ctx context.Context, method string, req interface{}, reply proto.Message, cc *grpc.ClientConn, invoker grpc.UnaryInvoker) error {
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+	// cancels the attempts still in flight once the call is committed to one
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		reply proto.Message
+		err   error
+	}
+	results := make(chan result, p.maxAttempts)
+	replyType := reflect.TypeOf(reply).Elem()
+	send := func() {
+		attemptReply := reflect.New(replyType).Interface().(proto.Message)
+		go func() {
+			results <- result{attemptReply, invoker(ctx, method, req, attemptReply, cc)}
+		}()
+	}
+
+	send()
+	sent, pending := 1, 1
+	timer := time.NewTimer(p.delay)
+	defer timer.Stop()
+	for {
+		select {
+		case res := <-results:
+			pending--
+			if res.err == nil {
+				reply.Reset()
+				proto.Merge(reply, res.reply)
+				return nil
+			}
+			if !p.nonFatal[status.Code(res.err)] || (sent == p.maxAttempts && pending == 0) {
+				return res.err
+			}
+			if sent == p.maxAttempts {
+				continue
+			}
+			// a non-fatal failure sends the next attempt right away
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		case <-timer.C:
+			if sent == p.maxAttempts {
+				continue
+			}
+		}
+		send()
+		sent++
+		pending++
+		timer.Reset(p.delay)
+	}
+}
@@ -0,0 +1,136 @@
+package grpcclient
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseHedgingPolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{"empty", `{}`, false},
+		{"hedging and retry", `{"methodConfig": [{"name": [{"service": "s"}], "retryPolicy": {}, "hedgingPolicy": {"maxAttempts": 2}}]}`, true},
+		{"single attempt", `{"methodConfig": [{"name": [{"service": "s"}], "hedgingPolicy": {"maxAttempts": 1}}]}`, true},
+		{"delay not in seconds", `{"methodConfig": [{"name": [{"service": "s"}], "hedgingPolicy": {"maxAttempts": 2, "hedgingDelay": "50ms"}}]}`, true},
+		{"method without service", `{"methodConfig": [{"name": [{"method": "m"}], "hedgingPolicy": {"maxAttempts": 2}}]}`, true},
+		{"duplicate name", `{"methodConfig": [{"name": [{"service": "s"}]}, {"name": [{"service": "s"}]}]}`, true},
+		{"invalid json", `{"methodConfig": [`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseHedgingPolicies(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("parseHedgingPolicies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHedgingPoliciesLookup(t *testing.T) {
+	policies, err := parseHedgingPolicies(`{"methodConfig": [
+		{"name": [{}], "hedgingPolicy": {"maxAttempts": 2, "hedgingDelay": "1s"}},
+		{"name": [{"service": "pkg.Service"}], "timeout": "2s", "hedgingPolicy": {"maxAttempts": 9, "hedgingDelay": "0.05s", "nonFatalStatusCodes": ["UNAVAILABLE"]}},
+		{"name": [{"service": "pkg.Service", "method": "Write"}]}
+	]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method      string
+		wantNil     bool
+		wantDelay   time.Duration
+		wantMaxAtts int
+	}{
+		{"/pkg.Service/Read", false, 50 * time.Millisecond, maxHedgedAttempts},
+		{"/pkg.Service/Write", true, 0, 0},
+		{"/other.Service/Read", false, time.Second, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			policy := policies.lookup(tt.method)
+			if tt.wantNil {
+				if policy != nil {
+					t.Errorf("lookup() = %+v, want no policy", policy)
+				}
+				return
+			}
+			if policy == nil || policy.delay != tt.wantDelay || policy.maxAttempts != tt.wantMaxAtts {
+				t.Errorf("lookup() = %+v, want a delay of %v and %d attempts", policy, tt.wantDelay, tt.wantMaxAtts)
+			}
+		})
+	}
+
+	if p := policies.lookup("/pkg.Service/Read"); p.timeout != 2*time.Second || !p.nonFatal[codes.Unavailable] {
+		t.Errorf("lookup() = %+v, want a timeout of 2s and UNAVAILABLE as non fatal", p)
+	}
+}
+
+func TestHedgingPolicyInvoke(t *testing.T) {
+	policy := &hedgingPolicy{
+		maxAttempts: 3,
+		delay:       20 * time.Millisecond,
+		nonFatal:    map[codes.Code]bool{codes.Unavailable: true},
+	}
+
+	tests := []struct {
+		name string
+		// attempt returns the outcome of the nth attempt, counting from 0
+		attempt      func(ctx context.Context, n int32) (string, error)
+		want         string
+		wantCode     codes.Code
+		wantAttempts int32
+	}{
+		{"first attempt succeeds", func(ctx context.Context, n int32) (string, error) {
+			return "first", nil
+		}, "first", codes.OK, 1},
+		{"slow attempt is hedged", func(ctx context.Context, n int32) (string, error) {
+			if n == 0 {
+				<-ctx.Done()
+				return "", status.FromContextError(ctx.Err()).Err()
+			}
+			return "hedged", nil
+		}, "hedged", codes.OK, 2},
+		{"non fatal failure sends the next attempt", func(ctx context.Context, n int32) (string, error) {
+			if n < 2 {
+				return "", status.Error(codes.Unavailable, "down")
+			}
+			return "third", nil
+		}, "third", codes.OK, 3},
+		{"fatal failure is returned", func(ctx context.Context, n int32) (string, error) {
+			return "", status.Error(codes.InvalidArgument, "bad")
+		}, "", codes.InvalidArgument, 1},
+		{"last failure is returned", func(ctx context.Context, n int32) (string, error) {
+			return "", status.Error(codes.Unavailable, "down")
+		}, "", codes.Unavailable, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				value, err := tt.attempt(ctx, atomic.AddInt32(&attempts, 1)-1)
+				reply.(*wrapperspb.StringValue).Value = value
+				return err
+			}
+			reply := &wrapperspb.StringValue{}
+			err := policy.invoke(context.Background(), "/pkg.Service/Read", nil, reply, nil, invoker)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("invoke() error = %v, want %v", err, tt.wantCode)
+			}
+			if reply.Value != tt.want {
+				t.Errorf("invoke() reply = %q, want %q", reply.Value, tt.want)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("invoke() made %d attempts, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// RequestIDHeader is the metadata key of the request id, sent back in the response header
+	RequestIDHeader = "x-request-id"
+
+	// maxRequestIDLength caps the request ids accepted from callers, longer ones are replaced
+	maxRequestIDLength = 128
+
+	healthServicePrefix = "/grpc.health.v1.Health/"
+)
+
+// UnaryServerInterceptor logs every unary call once it is handled and hands the
+// handler a logger with the request id through FromContext
+func UnaryServerInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		id := requestID(ctx)
+		grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, id))
+		callLogger := requestLogger(ctx, logger, id, info.FullMethod)
+
+		res, err := handler(NewContext(ctx, callLogger.Sugar()), req)
+		logCall(callLogger, info.FullMethod, start, err)
+		return res, err
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of UnaryServerInterceptor,
+// also logging how many messages went each way
+func StreamServerInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := ss.Context()
+		id := requestID(ctx)
+		ss.SetHeader(metadata.Pairs(RequestIDHeader, id))
+		callLogger := requestLogger(ctx, logger, id, info.FullMethod)
+
+		stream := &serverStream{ServerStream: ss, ctx: NewContext(ctx, callLogger.Sugar())}
+		err := handler(srv, stream)
+		logCall(callLogger.With(
+			zap.Int64("grpc.msgs_received", atomic.LoadInt64(&stream.received)),
+			zap.Int64("grpc.msgs_sent", atomic.LoadInt64(&stream.sent)),
+		), info.FullMethod, start, err)
+		return err
+	}
+}
+
+// requestID returns the request id sent by the caller, or a new one when the
+// caller sent none or an invalid one
+func requestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if ids := md.Get(RequestIDHeader); len(ids) > 0 && validRequestID(ids[0]) {
+		return ids[0]
+	}
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID reports whether id is made of letters, digits, dots, dashes and
+// underscores only, so that it cannot forge log lines or response headers
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '.' || c == '_' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// requestLogger returns the logger of a call, with the fields identifying it
+func requestLogger(ctx context.Context, logger *zap.Logger, id, fullMethod string) *zap.Logger {
+	fields := []zap.Field{
+		zap.String("request_id", id),
+		zap.String("grpc.method", strings.TrimPrefix(fullMethod, "/")),
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		fields = append(fields, zap.Stringer("peer", p.Addr))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields = append(fields, zap.Stringer("trace_id", sc.TraceID()))
+	}
+	return logger.With(fields...)
+}
+
+func logCall(logger *zap.Logger, fullMethod string, start time.Time, err error) {
+	st := status.Convert(err)
+	level := codeLevel(st.Code())
+	// probes would flood the logs
+	if st.Code() == codes.OK && strings.HasPrefix(fullMethod, healthServicePrefix) {
+		level = zapcore.DebugLevel
+	}
+	if ce := logger.Check(level, "finished call"); ce != nil {
+		fields := []zap.Field{
+			zap.String("grpc.code", st.Code().String()),
+			zap.Duration("duration", time.Since(start)),
+		}
+		if err != nil {
+			fields = append(fields, zap.String("error", st.Message()))
+		}
+		ce.Write(fields...)
+	}
+}
+
+// codeLevel logs the errors of the callers at info level, the ones that may
+// need attention at warn level and the failures of the server at error level
+func codeLevel(code codes.Code) zapcore.Level {
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.Unauthenticated:
+		return zapcore.InfoLevel
+	case codes.DeadlineExceeded, codes.PermissionDenied, codes.ResourceExhausted, codes.FailedPrecondition,
+		codes.Aborted, codes.OutOfRange, codes.Unavailable:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+// serverStream carries the request logger in its context and counts its messages
+type serverStream struct {
+	grpc.ServerStream
+	ctx            context.Context
+	received, sent int64
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) SendMsg(msg interface{}) error {
+	err := s.ServerStream.SendMsg(msg)
+	if err == nil {
+		atomic.AddInt64(&s.sent, 1)
+	}
+	return err
+}
+
+func (s *serverStream) RecvMsg(msg interface{}) error {
+	err := s.ServerStream.RecvMsg(msg)
+	if err == nil {
+		atomic.AddInt64(&s.received, 1)
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"context"
+	"google.golang.org/grpc/metadata"
+	"strings"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name string
+		// sent is the x-request-id sent by the caller, none when empty
+		sent string
+		kept bool
+	}{
+		{"none", "", false},
+		{"uuid", "3f2b8a4e-9c1d-4e5f-8a7b-6c5d4e3f2a1b", true},
+		{"dots and underscores", "web_1.req-42", true},
+		{"longest accepted", strings.Repeat("a", maxRequestIDLength), true},
+		{"too long", strings.Repeat("a", maxRequestIDLength+1), false},
+		{"newline", "abc\n{\"level\":\"error\"}", false},
+		{"carriage return", "abc\rdef", false},
+		{"control character", "abc\x1b[31m", false},
+		{"space", "abc def", false},
+		{"quote", `abc"def`, false},
+		{"non ascii", "réquest", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.sent != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(RequestIDHeader, tt.sent))
+			}
+			id := requestID(ctx)
+			if kept := id == tt.sent; kept != tt.kept {
+				t.Errorf("requestID() = %q, want the sent id kept: %v", id, tt.kept)
+			}
+			if !validRequestID(id) {
+				t.Errorf("requestID() = %q is not a valid request id", id)
+			}
+		})
+	}
+}
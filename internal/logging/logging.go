@@ -0,0 +1,82 @@
+// Package logging builds the zap logger of the server and logs every RPC with a
+// request id, handing the handlers a logger that carries it.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// FormatConsole writes human readable lines
+	FormatConsole = "console"
+	// FormatJSON writes one JSON object per line
+	FormatJSON = "json"
+)
+
+// Config configures the logger
+type Config struct {
+	// Level is the minimum level logged: debug, info, warn or error
+	Level string `yaml:"level"`
+	// Format is console or json
+	Format string `yaml:"format"`
+}
+
+// DefaultConfig returns the configuration used for the settings left unset
+func DefaultConfig() Config {
+	return Config{
+		Level:  "info",
+		Format: FormatConsole,
+	}
+}
+
+// Validate returns an error when the level or the format is unknown
+func (c *Config) Validate() error {
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(c.Level)); err != nil {
+		return fmt.Errorf("unknown log level %q", c.Level)
+	}
+	if c.Format != FormatConsole && c.Format != FormatJSON {
+		return fmt.Errorf("unknown log format %q", c.Format)
+	}
+	return nil
+}
+
+// NewLogger returns a logger writing to stderr as configured
+func NewLogger(cfg Config) (*zap.Logger, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	var level zapcore.Level
+	level.UnmarshalText([]byte(cfg.Level))
+
+	zc := zap.NewProductionConfig()
+	zc.Level = zap.NewAtomicLevelAt(level)
+	// every request is logged, and the panics are logged with their own stack
+	zc.Sampling = nil
+	zc.DisableStacktrace = true
+	zc.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	if cfg.Format == FormatConsole {
+		zc.Encoding = "console"
+		zc.EncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	}
+	return zc.Build()
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying logger
+func NewContext(ctx context.Context, logger *zap.SugaredLogger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger of the request ctx belongs to, or the global
+// logger outside of a request
+func FromContext(ctx context.Context) *zap.SugaredLogger {
+	if logger, ok := ctx.Value(contextKey{}).(*zap.SugaredLogger); ok {
+		return logger
+	}
+	return zap.S()
+}
@@ -0,0 +1,189 @@
+// Package metrics records Prometheus metrics about the RPCs served by a gRPC server.
+package metrics
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
+)
+
+const (
+	typeUnary        = "unary"
+	typeClientStream = "client_stream"
+	typeServerStream = "server_stream"
+	typeBidiStream   = "bidi_stream"
+)
+
+// ServerMetrics counts the RPCs of a gRPC server per method. Register it with a
+// prometheus.Registerer and install its interceptors on the server.
+type ServerMetrics struct {
+	started     *prometheus.CounterVec
+	handled     *prometheus.CounterVec
+	inFlight    *prometheus.GaugeVec
+	duration    *prometheus.HistogramVec
+	msgReceived *prometheus.CounterVec
+	msgSent     *prometheus.CounterVec
+}
+
+// NewServerMetrics creates the metrics, every one labeled with the type, service and method of the RPC
+func NewServerMetrics() *ServerMetrics {
+	labels := []string{"grpc_type", "grpc_service", "grpc_method"}
+	return &ServerMetrics{
+		started: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_started_total",
+			Help: "Total number of RPCs started on the server.",
+		}, labels),
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Total number of RPCs completed on the server, regardless of success or failure.",
+		}, append(labels, "grpc_code")),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "grpc_server_in_flight",
+			Help: "Number of RPCs currently being handled by the server.",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Time taken by the server to handle RPCs, streams included.",
+			Buckets: prometheus.DefBuckets,
+		}, append(labels, "grpc_code")),
+		msgReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_received_total",
+			Help: "Total number of stream messages received by the server.",
+		}, labels),
+		msgSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_sent_total",
+			Help: "Total number of stream messages sent by the server.",
+		}, labels),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (m *ServerMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.started.Describe(ch)
+	m.handled.Describe(ch)
+	m.inFlight.Describe(ch)
+	m.duration.Describe(ch)
+	m.msgReceived.Describe(ch)
+	m.msgSent.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (m *ServerMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.started.Collect(ch)
+	m.handled.Collect(ch)
+	m.inFlight.Collect(ch)
+	m.duration.Collect(ch)
+	m.msgReceived.Collect(ch)
+	m.msgSent.Collect(ch)
+}
+
+// InitializeMetrics exports zero values for every method registered on s so that
+// rates can be computed from the first RPC on. Call it after registering the services.
+func (m *ServerMetrics) InitializeMetrics(s *grpc.Server) {
+	for service, info := range s.GetServiceInfo() {
+		for _, method := range info.Methods {
+			rpcType := streamType(method.IsClientStream, method.IsServerStream)
+			m.started.WithLabelValues(rpcType, service, method.Name)
+			m.inFlight.WithLabelValues(rpcType, service, method.Name)
+			if method.IsClientStream || method.IsServerStream {
+				m.msgReceived.WithLabelValues(rpcType, service, method.Name)
+				m.msgSent.WithLabelValues(rpcType, service, method.Name)
+			}
+		}
+	}
+}
+
+// UnaryServerInterceptor records the metrics of the unary calls
+func (m *ServerMetrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		r := m.start(typeUnary, info.FullMethod)
+		res, err := handler(ctx, req)
+		r.done(err)
+		return res, err
+	}
+}
+
+// StreamServerInterceptor records the metrics of the streaming calls, counting
+// the messages they receive and send
+func (m *ServerMetrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		r := m.start(streamType(info.IsClientStream, info.IsServerStream), info.FullMethod)
+		err := handler(srv, &serverStream{
+			ServerStream: ss,
+			received:     m.msgReceived.WithLabelValues(r.labels...),
+			sent:         m.msgSent.WithLabelValues(r.labels...),
+		})
+		r.done(err)
+		return err
+	}
+}
+
+// rpc is a call being handled
+type rpc struct {
+	m      *ServerMetrics
+	labels []string
+	start  time.Time
+}
+
+func (m *ServerMetrics) start(rpcType, fullMethod string) *rpc {
+	service, method := splitMethod(fullMethod)
+	r := &rpc{m: m, labels: []string{rpcType, service, method}, start: time.Now()}
+	m.started.WithLabelValues(r.labels...).Inc()
+	m.inFlight.WithLabelValues(r.labels...).Inc()
+	return r
+}
+
+func (r *rpc) done(err error) {
+	code := status.Code(err).String()
+	r.m.inFlight.WithLabelValues(r.labels...).Dec()
+	r.m.handled.WithLabelValues(append(r.labels, code)...).Inc()
+	r.m.duration.WithLabelValues(append(r.labels, code)...).Observe(time.Since(r.start).Seconds())
+}
+
+// serverStream counts the messages going through a grpc.ServerStream
+type serverStream struct {
+	grpc.ServerStream
+	received prometheus.Counter
+	sent     prometheus.Counter
+}
+
+func (s *serverStream) SendMsg(msg interface{}) error {
+	err := s.ServerStream.SendMsg(msg)
+	if err == nil {
+		s.sent.Inc()
+	}
+	return err
+}
+
+func (s *serverStream) RecvMsg(msg interface{}) error {
+	err := s.ServerStream.RecvMsg(msg)
+	if err == nil {
+		s.received.Inc()
+	}
+	return err
+}
+
+func streamType(clientStream, serverStream bool) string {
+	switch {
+	case clientStream && serverStream:
+		return typeBidiStream
+	case clientStream:
+		return typeClientStream
+	case serverStream:
+		return typeServerStream
+	default:
+		return typeUnary
+	}
+}
+
+// splitMethod splits /package.Service/Method into package.Service and Method
+func splitMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.Index(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", "unknown"
+}
@@ -0,0 +1,102 @@
+// Package ratelimit limits how often every client may call the methods of the
+// server with token buckets, and how many calls it may make per day.
+package ratelimit
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// Config configures the limits. Calls to the methods matched by no rule are not limited.
+//
+//	rules:
+//	  - method: /calculatorpb.CalculatorService/PrimeNumberDecomposition
+//	    rate: 5
+//	    burst: 10
+//	    daily_quota: 10000
+//	  - method: /blog.BlogService/*
+//	    rate: 20
+//	    burst: 40
+type Config struct {
+	// Rules are tried in order, the first one matching the method applies
+	Rules []Rule `yaml:"rules"`
+	// APIKeyHeader is the metadata key of the API keys identifying the clients
+	// that are not authenticated. The keys are not verified by the server, only
+	// set it when a proxy in front of the server does, otherwise clients can
+	// escape their limits by sending new keys.
+	APIKeyHeader string `yaml:"api_key_header"`
+	// QuotaFile keeps the daily usage across restarts, it is lost on restart when empty
+	QuotaFile string `yaml:"quota_file"`
+	// SaveInterval is how often the daily usage is written to QuotaFile
+	SaveInterval time.Duration `yaml:"save_interval"`
+}
+
+// Rule limits the calls to the methods matching Method, counting the calls of
+// every client separately. The calls to all the methods a rule matches share
+// the limits of the client.
+type Rule struct {
+	// Method is a fully-qualified method like /package.Service/Method and may use
+	// the wildcards of path.Match, a lone "*" matches every method
+	Method string `yaml:"method"`
+	// Rate is how many calls per second a client may make in the long run, 0 for no limit
+	Rate float64 `yaml:"rate"`
+	// Burst is how many calls a client may make at once
+	Burst int `yaml:"burst"`
+	// DailyQuota is how many calls a client may make per UTC day, 0 for no limit
+	DailyQuota int64 `yaml:"daily_quota"`
+}
+
+// DefaultConfig returns the configuration used for the settings left unset
+func DefaultConfig() Config {
+	return Config{SaveInterval: 10 * time.Second}
+}
+
+// Enabled reports whether any method is limited
+func (c *Config) Enabled() bool {
+	return len(c.Rules) > 0
+}
+
+// Validate returns an error when a rule is invalid
+func (c *Config) Validate() error {
+	for _, r := range c.Rules {
+		if _, err := path.Match(methodPattern(r.Method), ""); err != nil {
+			return fmt.Errorf("invalid rate limited method %q: %v", r.Method, err)
+		}
+		if r.Rate < 0 || r.DailyQuota < 0 {
+			return fmt.Errorf("rate limit of %s cannot be negative", r.Method)
+		}
+		if r.Rate > 0 && r.Burst < 1 {
+			return fmt.Errorf("rate limit of %s needs a burst of at least 1", r.Method)
+		}
+		if r.Rate == 0 && r.DailyQuota == 0 {
+			return fmt.Errorf("rate limit of %s sets neither a rate nor a daily quota", r.Method)
+		}
+	}
+	if c.QuotaFile != "" && c.SaveInterval <= 0 {
+		return fmt.Errorf("quota save interval must be positive")
+	}
+	return nil
+}
+
+// match returns the index of the rule applying to method, or -1
+func (c *Config) match(method string) int {
+	for i, r := range c.Rules {
+		if r.Method == "*" {
+			return i
+		}
+		if ok, _ := path.Match(methodPattern(r.Method), method); ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// methodPattern is lenient with the leading slash grpc puts in front of the service
+func methodPattern(method string) string {
+	if method != "*" && !strings.HasPrefix(method, "/") {
+		return "/" + method
+	}
+	return method
+}
@@ -0,0 +1,56 @@
+package ratelimit
+
+import "testing"
+
+func TestConfigMatch(t *testing.T) {
+	c := Config{Rules: []Rule{
+		{Method: "/calculatorpb.CalculatorService/PrimeNumberDecomposition", Rate: 1, Burst: 1},
+		{Method: "blog.BlogService/*", Rate: 1, Burst: 1},
+		{Method: "*", DailyQuota: 10},
+	}}
+
+	tests := []struct {
+		method string
+		want   int
+	}{
+		{"/calculatorpb.CalculatorService/PrimeNumberDecomposition", 0},
+		{"/blog.BlogService/CreateBlog", 1},
+		{"/calculatorpb.CalculatorService/Sum", 2},
+		{"/greet.GreetService/Greet", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			if got := c.match(tt.method); got != tt.want {
+				t.Errorf("match(%q) = %d, want %d", tt.method, got, tt.want)
+			}
+		})
+	}
+
+	if got := (&Config{Rules: c.Rules[:2]}).match("/greet.GreetService/Greet"); got != -1 {
+		t.Errorf("match() of a method without a rule = %d, want -1", got)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"no rules", DefaultConfig(), false},
+		{"rate and quota", Config{Rules: []Rule{{Method: "*", Rate: 5, Burst: 10, DailyQuota: 100}}}, false},
+		{"quota only", Config{Rules: []Rule{{Method: "/blog.BlogService/*", DailyQuota: 100}}}, false},
+		{"invalid pattern", Config{Rules: []Rule{{Method: "/blog.BlogService/[", Rate: 1, Burst: 1}}}, true},
+		{"negative rate", Config{Rules: []Rule{{Method: "*", Rate: -1, Burst: 1}}}, true},
+		{"rate without burst", Config{Rules: []Rule{{Method: "*", Rate: 1}}}, true},
+		{"no limit", Config{Rules: []Rule{{Method: "*"}}}, true},
+		{"quota file without save interval", Config{QuotaFile: "quota.json"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"github.com/naraycitra/grpc-go-adventure/internal/auth"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// RetryAfterTrailer is the trailer of the rejected calls telling in how many
+	// seconds the client may call again
+	RetryAfterTrailer = "retry-after"
+
+	// sweepInterval is how often the buckets of the idle clients are dropped
+	sweepInterval = time.Minute
+
+	// ProxyKeyHeader is the metadata key of the secret that marks the calls of
+	// the in-process HTTP gateway, whose X-Forwarded-For header is trusted
+	ProxyKeyHeader = "x-ratelimit-proxy-key"
+)
+
+// Limiter rejects the calls of the clients over their limits with RESOURCE_EXHAUSTED
+type Limiter struct {
+	cfg    Config
+	logger *zap.SugaredLogger
+	// proxyKey is the secret sent by the proxies given ProxyCredentials
+	proxyKey string
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*bucket
+	lastSweep time.Time
+	quotas    *quotaStore
+
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// bucketKey tells apart the buckets of every client for every rule
+type bucketKey struct {
+	rule   int
+	client string
+}
+
+// bucket holds the calls a client may still make, refilled at the rate of its rule
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns a limiter enforcing cfg, which loads the daily usage from
+// cfg.QuotaFile and saves it there regularly until Close is called
+func NewLimiter(cfg Config, logger *zap.SugaredLogger) (*Limiter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	quotas, err := loadQuotas(cfg.QuotaFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load the quota usage: %v", err)
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	l := &Limiter{
+		cfg:       cfg,
+		logger:    logger,
+		proxyKey:  hex.EncodeToString(key),
+		buckets:   make(map[bucketKey]*bucket),
+		lastSweep: time.Now(),
+		quotas:    quotas,
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	go l.saveQuotas()
+	return l, nil
+}
+
+// Close stops saving the daily usage and saves it a last time
+func (l *Limiter) Close() error {
+	close(l.done)
+	<-l.stopped
+	return l.save()
+}
+
+func (l *Limiter) saveQuotas() {
+	defer close(l.stopped)
+	if l.cfg.QuotaFile == "" {
+		return
+	}
+	ticker := time.NewTicker(l.cfg.SaveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := l.save(); err != nil {
+				l.logger.Errorf("Cannot save the quota usage: %v", err)
+			}
+		case <-l.done:
+			return
+		}
+	}
+}
+
+// save writes the daily usage to the quota file when it changed
+func (l *Limiter) save() error {
+	if l.cfg.QuotaFile == "" {
+		return nil
+	}
+	l.mu.Lock()
+	b, changed, err := l.quotas.snapshot()
+	l.mu.Unlock()
+	if err != nil || !changed {
+		return err
+	}
+	if err := writeQuotas(l.cfg.QuotaFile, b); err != nil {
+		// try again on the next save
+		l.mu.Lock()
+		l.quotas.changed = true
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// allow takes a call to method from the limits of the caller. It returns a
+// RESOURCE_EXHAUSTED error and when the caller may retry once it is over them.
+func (l *Limiter) allow(ctx context.Context, method string) (retryAfter time.Duration, err error) {
+	i := l.cfg.match(method)
+	if i < 0 {
+		return 0, nil
+	}
+	rule := l.cfg.Rules[i]
+	client := l.clientKey(ctx)
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if rule.DailyQuota > 0 && l.quotas.used(now, rule.Method, client) >= rule.DailyQuota {
+		tomorrow := now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+		return tomorrow.Sub(now), status.Errorf(codes.ResourceExhausted, "daily quota of %d calls to %s exceeded", rule.DailyQuota, method)
+	}
+	if rule.Rate > 0 {
+		b := l.bucket(bucketKey{rule: i, client: client}, rule, now)
+		if b.tokens < 1 {
+			wait := time.Duration((1 - b.tokens) / rule.Rate * float64(time.Second))
+			return wait, status.Errorf(codes.ResourceExhausted, "rate limit of %s exceeded, retry in %v", method, wait.Round(time.Millisecond))
+		}
+		b.tokens--
+	}
+	if rule.DailyQuota > 0 {
+		l.quotas.add(now, rule.Method, client)
+	}
+	return 0, nil
+}
+
+// bucket returns the bucket of key refilled up to now, l.mu must be held
+func (l *Limiter) bucket(key bucketKey, rule Rule, now time.Time) *bucket {
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		l.sweep(now)
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(rule.Burst), last: now}
+		l.buckets[key] = b
+		return b
+	}
+	b.tokens = math.Min(float64(rule.Burst), b.tokens+now.Sub(b.last).Seconds()*rule.Rate)
+	b.last = now
+	return b
+}
+
+// sweep drops the buckets that would be full by now, they are recreated full
+// on the next call of their client
+func (l *Limiter) sweep(now time.Time) {
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		rule := l.cfg.Rules[key.rule]
+		if b.tokens+now.Sub(b.last).Seconds()*rule.Rate >= float64(rule.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// clientKey identifies the caller by its principal, else by its API key when
+// the API key header is set, else by its IP address
+func (l *Limiter) clientKey(ctx context.Context) string {
+	if p, ok := auth.FromContext(ctx); ok {
+		return "principal:" + p.Subject
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if l.cfg.APIKeyHeader != "" {
+		if keys := md.Get(l.cfg.APIKeyHeader); len(keys) > 0 && keys[0] != "" {
+			// the keys are secrets, keep them out of the quota file
+			sum := sha256.Sum256([]byte(keys[0]))
+			return "api-key:" + hex.EncodeToString(sum[:8])
+		}
+	}
+	// the proxies append the address of their own client to X-Forwarded-For, the
+	// addresses before it are the ones their client claims
+	if l.fromProxy(md) {
+		if fwd := md.Get("x-forwarded-for"); len(fwd) > 0 {
+			hops := strings.Split(fwd[len(fwd)-1], ",")
+			if last := strings.TrimSpace(hops[len(hops)-1]); last != "" {
+				return "ip:" + last
+			}
+		}
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "unknown"
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return "ip:" + host
+	}
+	return "ip:" + p.Addr.String()
+}
+
+// fromProxy reports whether the call was made with ProxyCredentials
+func (l *Limiter) fromProxy(md metadata.MD) bool {
+	for _, key := range md.Get(ProxyKeyHeader) {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(l.proxyKey)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// ProxyCredentials returns the per-RPC credentials of a client connection of the
+// process that forwards calls for others, like the HTTP gateway does, so that
+// they are limited by the X-Forwarded-For address rather than the proxy one
+func (l *Limiter) ProxyCredentials() credentials.PerRPCCredentials {
+	return proxyCredentials{key: l.proxyKey}
+}
+
+type proxyCredentials struct {
+	key string
+}
+
+func (c proxyCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{ProxyKeyHeader: c.key}, nil
+}
+
+// RequireTransportSecurity is false since the proxies are in the process and
+// call the server over the loopback
+func (c proxyCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// retryTrailer tells the client to wait at least retryAfter, in whole seconds
+func retryTrailer(retryAfter time.Duration) metadata.MD {
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return metadata.Pairs(RetryAfterTrailer, strconv.FormatInt(seconds, 10))
+}
+
+// UnaryServerInterceptor rejects the unary calls over the limits of their client.
+// It identifies the authenticated clients by the principal set by
+// auth.UnaryServerInterceptor, which must run before it.
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if retryAfter, err := l.allow(ctx, info.FullMethod); err != nil {
+			grpc.SetTrailer(ctx, retryTrailer(retryAfter))
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of UnaryServerInterceptor,
+// a stream counts as one call
+func (l *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if retryAfter, err := l.allow(ss.Context(), info.FullMethod); err != nil {
+			ss.SetTrailer(retryTrailer(retryAfter))
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
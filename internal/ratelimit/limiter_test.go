@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
+	"testing"
+	"time"
+)
+
+func newTestLimiter(t *testing.T, rules ...Rule) *Limiter {
+	t.Helper()
+	cfg := DefaultConfig()
+	cfg.Rules = rules
+	l, err := NewLimiter(cfg, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l
+}
+
+func peerContext(ip string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 4000}})
+}
+
+func TestLimiterAllow(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  Rule
+		calls int
+		// allowed is how many of the calls get through
+		allowed int
+	}{
+		{"burst", Rule{Method: "*", Rate: 0.001, Burst: 3}, 5, 3},
+		{"daily quota", Rule{Method: "*", DailyQuota: 2}, 4, 2},
+		{"burst within the daily quota", Rule{Method: "*", Rate: 0.001, Burst: 5, DailyQuota: 2}, 4, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestLimiter(t, tt.rule)
+			ctx := peerContext("10.0.0.1")
+			allowed := 0
+			for i := 0; i < tt.calls; i++ {
+				retryAfter, err := l.allow(ctx, "/greet.GreetService/Greet")
+				if err == nil {
+					allowed++
+					continue
+				}
+				if status.Code(err) != codes.ResourceExhausted || retryAfter <= 0 {
+					t.Fatalf("allow() = %v, %v, want RESOURCE_EXHAUSTED and a positive retry delay", retryAfter, err)
+				}
+			}
+			if allowed != tt.allowed {
+				t.Errorf("allowed %d of %d calls, want %d", allowed, tt.calls, tt.allowed)
+			}
+
+			// the other clients have limits of their own
+			if _, err := l.allow(peerContext("10.0.0.2"), "/greet.GreetService/Greet"); err != nil {
+				t.Errorf("allow() of another client error = %v", err)
+			}
+		})
+	}
+}
+
+func TestLimiterRefills(t *testing.T) {
+	l := newTestLimiter(t, Rule{Method: "*", Rate: 1000, Burst: 1})
+	ctx := peerContext("10.0.0.1")
+	if _, err := l.allow(ctx, "/greet.GreetService/Greet"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.allow(ctx, "/greet.GreetService/Greet"); err == nil {
+		t.Fatal("allow() over the burst error = nil")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := l.allow(ctx, "/greet.GreetService/Greet"); err != nil {
+		t.Errorf("allow() after the refill error = %v", err)
+	}
+}
+
+func TestLimiterClientKey(t *testing.T) {
+	l := newTestLimiter(t, Rule{Method: "*", DailyQuota: 1})
+	l.cfg.APIKeyHeader = "x-api-key"
+	withMD := func(pairs ...string) context.Context {
+		return metadata.NewIncomingContext(peerContext("127.0.0.1"), metadata.Pairs(pairs...))
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"peer address", peerContext("10.0.0.1"), "ip:10.0.0.1"},
+		{"no peer", context.Background(), "unknown"},
+		{"spoofed forwarded address", withMD("x-forwarded-for", "10.0.0.9"), "ip:127.0.0.1"},
+		{"wrong proxy key", withMD(ProxyKeyHeader, "guess", "x-forwarded-for", "10.0.0.9"), "ip:127.0.0.1"},
+		{"proxy", withMD(ProxyKeyHeader, l.proxyKey, "x-forwarded-for", "10.0.0.8, 10.0.0.9"), "ip:10.0.0.9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := l.clientKey(tt.ctx); got != tt.want {
+				t.Errorf("clientKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	apiKey := l.clientKey(withMD("x-api-key", "secret"))
+	if apiKey == "ip:127.0.0.1" || apiKey != l.clientKey(withMD("x-api-key", "secret")) {
+		t.Errorf("clientKey() of an API key = %q, want a stable key of its own", apiKey)
+	}
+}
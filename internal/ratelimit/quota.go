@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// dayLayout formats the UTC day the usage is counted for
+const dayLayout = "2006-01-02"
+
+// quotaStore counts the calls of every client for the rules with a daily quota.
+// It is not safe for concurrent use.
+type quotaStore struct {
+	usage quotaUsage
+	// changed tells whether usage differs from the quota file
+	changed bool
+}
+
+// quotaUsage is the JSON layout of the quota file
+type quotaUsage struct {
+	Day string `json:"day"`
+	// Calls are keyed by the method pattern of the rule and the client
+	Calls map[string]int64 `json:"calls"`
+}
+
+// loadQuotas reads the usage saved in filename, the usage of a past day is dropped
+func loadQuotas(filename string) (*quotaStore, error) {
+	q := &quotaStore{usage: quotaUsage{Calls: make(map[string]int64)}}
+	if filename == "" {
+		return q, nil
+	}
+	b, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &q.usage); err != nil {
+		return nil, err
+	}
+	if q.usage.Calls == nil {
+		q.usage.Calls = make(map[string]int64)
+	}
+	return q, nil
+}
+
+// used returns how many calls client made today to the methods of the rule
+func (q *quotaStore) used(now time.Time, method, client string) int64 {
+	q.roll(now)
+	return q.usage.Calls[method+" "+client]
+}
+
+func (q *quotaStore) add(now time.Time, method, client string) {
+	q.roll(now)
+	q.usage.Calls[method+" "+client]++
+	q.changed = true
+}
+
+// roll starts counting again on a new day
+func (q *quotaStore) roll(now time.Time) {
+	if day := now.UTC().Format(dayLayout); q.usage.Day != day {
+		q.usage = quotaUsage{Day: day, Calls: make(map[string]int64)}
+		q.changed = true
+	}
+}
+
+// snapshot returns the usage to save when it changed since the last snapshot
+func (q *quotaStore) snapshot() (b []byte, changed bool, err error) {
+	if !q.changed {
+		return nil, false, nil
+	}
+	b, err = json.Marshal(q.usage)
+	if err != nil {
+		return nil, false, err
+	}
+	q.changed = false
+	return b, true, nil
+}
+
+// writeQuotas replaces filename with b, never leaving a partially written file
+func writeQuotas(filename string, b []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
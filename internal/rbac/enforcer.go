@@ -0,0 +1,111 @@
+package rbac
+
+import (
+	"context"
+	"github.com/naraycitra/grpc-go-adventure/internal/auth"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// Enforcer checks every RPC against the current policy, which can be reloaded
+// from its file while the server runs
+type Enforcer struct {
+	filename string
+	logger   *zap.SugaredLogger
+
+	mu     sync.RWMutex
+	policy *Policy
+}
+
+// NewEnforcer loads the policy from filename and logs the denials to logger
+func NewEnforcer(filename string, logger *zap.SugaredLogger) (*Enforcer, error) {
+	policy, err := LoadPolicy(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &Enforcer{filename: filename, logger: logger, policy: policy}, nil
+}
+
+// Reload reads the policy file again. The current policy stays in force when
+// the file is invalid.
+func (e *Enforcer) Reload() error {
+	policy, err := LoadPolicy(e.filename)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	e.policy = policy
+	e.mu.Unlock()
+	return nil
+}
+
+// ReloadOnSIGHUP reloads the policy every time the process receives SIGHUP
+// until stop is called
+func (e *Enforcer) ReloadOnSIGHUP() (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ch:
+				if err := e.Reload(); err != nil {
+					e.logger.Errorf("Cannot reload access policy, keeping the current one: %v", err)
+				} else {
+					e.logger.Infof("Access policy reloaded from %s", e.filename)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
+
+// authorize returns PERMISSION_DENIED unless a role of the caller may call method
+func (e *Enforcer) authorize(ctx context.Context, method string) error {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		e.logger.Warnf("Access denied to %s: caller is not authenticated", method)
+		return status.Errorf(codes.PermissionDenied, "access to %s denied", method)
+	}
+
+	e.mu.RLock()
+	allowed := e.policy.Allowed(p.Roles, method)
+	e.mu.RUnlock()
+	if !allowed {
+		e.logger.Warnf("Access denied to %s for %s with roles %v", method, p.Subject, p.Roles)
+		return status.Errorf(codes.PermissionDenied, "access to %s denied", method)
+	}
+	return nil
+}
+
+// UnaryServerInterceptor rejects the unary calls the policy does not allow. It relies
+// on the principal set by auth.UnaryServerInterceptor, which must run before it.
+func (e *Enforcer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := e.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of UnaryServerInterceptor
+func (e *Enforcer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := e.authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,75 @@
+// Package rbac grants the roles of the authenticated callers access to the RPCs
+// listed for them in a YAML policy file.
+package rbac
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+// Policy maps every role to the methods it may call
+type Policy struct {
+	roles map[string][]string
+}
+
+// policyFile is the YAML layout of a policy:
+//
+//	roles:
+//	  admin:
+//	    - "*"
+//	  reader:
+//	    - /blog.BlogService/Read*
+//	    - /greet.GreetService/*
+type policyFile struct {
+	Roles map[string][]string `yaml:"roles"`
+}
+
+// LoadPolicy reads the policy from a YAML file. Methods are fully-qualified like
+// /package.Service/Method and may use the wildcards of path.Match, a lone "*"
+// matches every method of every service.
+func LoadPolicy(filename string) (*Policy, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var f policyFile
+	if err := yaml.UnmarshalStrict(b, &f); err != nil {
+		return nil, fmt.Errorf("cannot parse policy %s: %v", filename, err)
+	}
+	if len(f.Roles) == 0 {
+		return nil, fmt.Errorf("policy %s defines no roles", filename)
+	}
+
+	p := &Policy{roles: make(map[string][]string, len(f.Roles))}
+	for role, methods := range f.Roles {
+		for _, m := range methods {
+			if m != "*" && !strings.HasPrefix(m, "/") {
+				// be lenient with the leading slash grpc puts in front of the service
+				m = "/" + m
+			}
+			if _, err := path.Match(m, ""); err != nil {
+				return nil, fmt.Errorf("invalid method %q of role %s: %v", m, role, err)
+			}
+			p.roles[role] = append(p.roles[role], m)
+		}
+	}
+	return p, nil
+}
+
+// Allowed reports whether any of the roles may call the fully-qualified method
+func (p *Policy) Allowed(roles []string, method string) bool {
+	for _, role := range roles {
+		for _, pattern := range p.roles[role] {
+			if pattern == "*" {
+				return true
+			}
+			if ok, _ := path.Match(pattern, method); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
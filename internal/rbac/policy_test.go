@@ -0,0 +1,89 @@
+package rbac
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPolicyAllowed(t *testing.T) {
+	p := writePolicy(t, `
+roles:
+  admin:
+    - "*"
+  reader:
+    - /blog.BlogService/Read*
+    - /blog.BlogService/ListBlog
+    - greet.GreetService/*
+  calculator:
+    - /calculatorpb.CalculatorService/?um
+`)
+
+	tests := []struct {
+		name   string
+		roles  []string
+		method string
+		want   bool
+	}{
+		{"lone wildcard", []string{"admin"}, "/blog.BlogService/DeleteBlog", true},
+		{"prefix wildcard", []string{"reader"}, "/blog.BlogService/ReadBlog", true},
+		{"exact method", []string{"reader"}, "/blog.BlogService/ListBlog", true},
+		{"method not listed", []string{"reader"}, "/blog.BlogService/DeleteBlog", false},
+		{"exact method does not match a prefix", []string{"reader"}, "/blog.BlogService/ListBlogRevisions", false},
+		{"leading slash added", []string{"reader"}, "/greet.GreetService/Greet", true},
+		{"wildcard does not cross services", []string{"reader"}, "/greet.GreetServiceV2/Greet", false},
+		{"single character wildcard", []string{"calculator"}, "/calculatorpb.CalculatorService/Sum", true},
+		{"single character wildcard needs one character", []string{"calculator"}, "/calculatorpb.CalculatorService/um", false},
+		{"any of the roles", []string{"calculator", "reader"}, "/blog.BlogService/ReadBlog", true},
+		{"unknown role", []string{"writer"}, "/blog.BlogService/ReadBlog", false},
+		{"no role", nil, "/blog.BlogService/ReadBlog", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Allowed(tt.roles, tt.method); got != tt.want {
+				t.Errorf("Allowed(%v, %q) = %v, want %v", tt.roles, tt.method, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPolicyErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+	}{
+		{"no roles", "roles: {}\n"},
+		{"unknown field", "roles:\n  admin: [\"*\"]\nusers: []\n"},
+		{"invalid pattern", "roles:\n  reader:\n    - /blog.BlogService/[Read\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "policy.yaml")
+			if err := ioutil.WriteFile(filename, []byte(tt.policy), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadPolicy(filename); err == nil {
+				t.Error("LoadPolicy() error = nil, want an error")
+			}
+		})
+	}
+
+	if _, err := LoadPolicy(filepath.Join(t.TempDir(), "missing.yaml")); !os.IsNotExist(err) {
+		t.Errorf("LoadPolicy() of a missing file error = %v, want a not exist error", err)
+	}
+}
+
+// writePolicy loads the policy from a temporary file
+func writePolicy(t *testing.T, policy string) *Policy {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := ioutil.WriteFile(filename, []byte(policy), 0600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadPolicy(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/jhump/protoreflect/dynamic/grpcdynamic"
+	"github.com/jhump/protoreflect/grpcreflect"
+	"google.golang.org/grpc"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// maxLineSize is the longest streamed request line accepted on stdin
+const maxLineSize = 16 << 20
+
+// call invokes the method named by args[0] with the JSON request of args[1] or stdin
+// and prints every response as a line of JSON
+func call(ctx context.Context, cc *grpc.ClientConn, refClient *grpcreflect.Client, args []string) error {
+	md, err := resolveMethod(refClient, args[0])
+	if err != nil {
+		return err
+	}
+
+	var input io.Reader = os.Stdin
+	if len(args) == 2 {
+		input = strings.NewReader(args[1])
+	}
+	// messages of any type the server knows about, for the Any fields
+	factory := dynamic.NewMessageFactoryWithDefaults()
+	resolver := dynamic.AnyResolver(factory, md.GetFile())
+	requests := &requestReader{
+		md:          md.GetInputType(),
+		factory:     factory,
+		unmarshaler: &jsonpb.Unmarshaler{AnyResolver: resolver},
+		input:       input,
+	}
+	marshaler := &jsonpb.Marshaler{AnyResolver: resolver}
+	printResponse := func(m proto.Message) error {
+		var js string
+		var err error
+		if dm, ok := m.(*dynamic.Message); ok {
+			var b []byte
+			b, err = dm.MarshalJSONPB(marshaler)
+			js = string(b)
+		} else {
+			js, err = marshaler.MarshalToString(m)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println(js)
+		return nil
+	}
+
+	stub := grpcdynamic.NewStubWithMessageFactory(cc, factory)
+	switch {
+	case !md.IsClientStreaming() && !md.IsServerStreaming():
+		req, err := requests.all()
+		if err != nil {
+			return err
+		}
+		res, err := stub.InvokeRpc(ctx, md, req)
+		if err != nil {
+			return err
+		}
+		return printResponse(res)
+
+	case !md.IsClientStreaming():
+		req, err := requests.all()
+		if err != nil {
+			return err
+		}
+		stream, err := stub.InvokeRpcServerStream(ctx, md, req)
+		if err != nil {
+			return err
+		}
+		for {
+			res, err := stream.RecvMsg()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := printResponse(res); err != nil {
+				return err
+			}
+		}
+
+	case !md.IsServerStreaming():
+		stream, err := stub.InvokeRpcClientStream(ctx, md)
+		if err != nil {
+			return err
+		}
+		for {
+			req, err := requests.next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if err := stream.SendMsg(req); err != nil {
+				// the reason is reported by CloseAndReceive
+				break
+			}
+		}
+		res, err := stream.CloseAndReceive()
+		if err != nil {
+			return err
+		}
+		return printResponse(res)
+
+	default:
+		stream, err := stub.InvokeRpcBidiStream(ctx, md)
+		if err != nil {
+			return err
+		}
+		// send while receiving so responses show up as soon as the server sends them
+		sendErr := make(chan error, 1)
+		go func() {
+			defer stream.CloseSend()
+			for {
+				req, err := requests.next()
+				if err == io.EOF {
+					sendErr <- nil
+					return
+				}
+				if err != nil {
+					sendErr <- err
+					return
+				}
+				if err := stream.SendMsg(req); err != nil {
+					sendErr <- nil
+					return
+				}
+			}
+		}()
+		for {
+			res, err := stream.RecvMsg()
+			if err == io.EOF {
+				return <-sendErr
+			}
+			if err != nil {
+				return err
+			}
+			if err := printResponse(res); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// resolveMethod finds a method named service/method or service.method
+func resolveMethod(refClient *grpcreflect.Client, name string) (*desc.MethodDescriptor, error) {
+	name = strings.TrimPrefix(name, "/")
+	i := strings.LastIndex(name, "/")
+	if i < 0 {
+		i = strings.LastIndex(name, ".")
+	}
+	if i < 0 {
+		return nil, fmt.Errorf("method %q is not in the service/method form", name)
+	}
+
+	sd, err := refClient.ResolveService(name[:i])
+	if err != nil {
+		return nil, fmt.Errorf("cannot find service %s: %v", name[:i], err)
+	}
+	md := sd.FindMethodByName(name[i+1:])
+	if md == nil {
+		return nil, fmt.Errorf("service %s has no method %s", name[:i], name[i+1:])
+	}
+	return md, nil
+}
+
+// requestReader parses the JSON requests of a call
+type requestReader struct {
+	md          *desc.MessageDescriptor
+	factory     *dynamic.MessageFactory
+	unmarshaler *jsonpb.Unmarshaler
+	input       io.Reader
+	scanner     *bufio.Scanner
+	line        int
+}
+
+// all parses the whole input as a single request, an empty input is an empty request
+func (r *requestReader) all() (proto.Message, error) {
+	b, err := ioutil.ReadAll(r.input)
+	if err != nil {
+		return nil, err
+	}
+	return r.parse(b)
+}
+
+// next parses the next non-empty line of the input, io.EOF means there is none left
+func (r *requestReader) next() (proto.Message, error) {
+	if r.scanner == nil {
+		r.scanner = bufio.NewScanner(r.input)
+		r.scanner.Buffer(nil, maxLineSize)
+	}
+	for r.scanner.Scan() {
+		r.line++
+		if len(strings.TrimSpace(r.scanner.Text())) == 0 {
+			continue
+		}
+		msg, err := r.parse(r.scanner.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", r.line, err)
+		}
+		return msg, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+func (r *requestReader) parse(js []byte) (proto.Message, error) {
+	msg := r.factory.NewDynamicMessage(r.md)
+	if len(strings.TrimSpace(string(js))) == 0 {
+		return msg, nil
+	}
+	if err := msg.UnmarshalJSONPB(r.unmarshaler, js); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", r.md.GetFullyQualifiedName(), err)
+	}
+	return msg, nil
+}
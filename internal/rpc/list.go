@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/grpcreflect"
+	"strings"
+)
+
+// list prints the services of the server, or the methods of the service in args
+func list(refClient *grpcreflect.Client, args []string) error {
+	if len(args) == 0 {
+		services, err := refClient.ListServices()
+		if err != nil {
+			return fmt.Errorf("cannot list services: %v", err)
+		}
+		for _, s := range services {
+			fmt.Println(s)
+		}
+		return nil
+	}
+
+	sd, err := refClient.ResolveService(args[0])
+	if err != nil {
+		return fmt.Errorf("cannot find service %s: %v", args[0], err)
+	}
+	for _, md := range sd.GetMethods() {
+		fmt.Println(signature(md))
+	}
+	return nil
+}
+
+// signature returns the method as it is declared in its .proto file
+func signature(md *desc.MethodDescriptor) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "rpc %s(", md.GetName())
+	if md.IsClientStreaming() {
+		b.WriteString("stream ")
+	}
+	fmt.Fprintf(&b, "%s) returns (", md.GetInputType().GetFullyQualifiedName())
+	if md.IsServerStreaming() {
+		b.WriteString("stream ")
+	}
+	fmt.Fprintf(&b, "%s)", md.GetOutputType().GetFullyQualifiedName())
+	return b.String()
+}
@@ -0,0 +1,136 @@
+// Command rpc lists the services of a gRPC server and calls their methods with
+// JSON messages, discovering the schema through server reflection.
+//
+//	rpc [-addr host:port] [-token jwt] list [service]
+//	rpc [-addr host:port] [-token jwt] call service/method [json]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/jhump/protoreflect/grpcreflect"
+	"github.com/naraycitra/grpc-go-adventure/internal/auth"
+	"github.com/naraycitra/grpc-go-adventure/internal/tlsconfig"
+	"github.com/naraycitra/grpc-go-adventure/internal/tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"os"
+	"strings"
+	"time"
+)
+
+// headers collects the repeated -H flags
+type headers []string
+
+func (h *headers) String() string {
+	return strings.Join(*h, ", ")
+}
+
+func (h *headers) Set(s string) error {
+	if !strings.Contains(s, ":") {
+		return fmt.Errorf("header %q is not in the name: value form", s)
+	}
+	*h = append(*h, s)
+	return nil
+}
+
+func main() {
+	var extra headers
+	addr := flag.String("addr", "localhost:50051", "address of the server")
+	token := flag.String("token", os.Getenv("RPC_TOKEN"), "bearer token sent with every call, defaults to $RPC_TOKEN")
+	timeout := flag.Duration("timeout", 0, "deadline of the whole command, 0 for none")
+	flag.Var(&extra, "H", "metadata sent with every call, as name: value, may be repeated")
+	useTLS := flag.Bool("tls", false, "dial with TLS, implied by the other -tls flags")
+	var tlsCfg tlsconfig.ClientConfig
+	flag.StringVar(&tlsCfg.CAFile, "tls-ca", "", "PEM bundle of the CAs verifying the server certificate, the system roots when empty")
+	flag.StringVar(&tlsCfg.CertFile, "tls-cert", "", "PEM client certificate for mutual TLS")
+	flag.StringVar(&tlsCfg.KeyFile, "tls-key", "", "PEM private key of the client certificate")
+	flag.StringVar(&tlsCfg.ServerName, "tls-server-name", "", "host name verified in the server certificate instead of the one of -addr")
+	traceCfg := tracing.DefaultConfig()
+	flag.StringVar(&traceCfg.Exporter, "trace", traceCfg.Exporter, "where spans are exported: none, stdout or otlp to $OTEL_EXPORTER_OTLP_ENDPOINT")
+	flag.StringVar(&traceCfg.File, "trace-file", "", "file the stdout trace exporter appends to instead of stderr")
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		fmt.Fprintf(out, "usage: %s [flags] list [service]\n", os.Args[0])
+		fmt.Fprintf(out, "       %s [flags] call service/method [json]\n\n", os.Args[0])
+		fmt.Fprintln(out, "call reads the request from stdin when json is omitted, one message per line")
+		fmt.Fprintln(out, "for client streaming methods, and writes one JSON response per line.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), traceCfg, "rpc")
+	if err != nil {
+		fatalf("cannot set up tracing: %v", err)
+	}
+	transport := grpc.WithInsecure()
+	if *useTLS || tlsCfg != (tlsconfig.ClientConfig{}) {
+		creds, err := tlsconfig.NewClientCredentials(tlsCfg)
+		if err != nil {
+			fatalf("cannot set up TLS: %v", err)
+		}
+		transport = grpc.WithTransportCredentials(creds)
+	}
+	opts := []grpc.DialOption{transport, grpc.WithStatsHandler(tracing.NewClientHandler())}
+	if *token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(auth.NewTokenCredentials(*token)))
+	}
+	cc, err := grpc.Dial(*addr, opts...)
+	if err != nil {
+		fatalf("cannot dial %s: %v", *addr, err)
+	}
+	defer cc.Close()
+
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	for _, h := range extra {
+		i := strings.Index(h, ":")
+		ctx = metadata.AppendToOutgoingContext(ctx, strings.TrimSpace(h[:i]), strings.TrimSpace(h[i+1:]))
+	}
+
+	refClient := grpcreflect.NewClient(ctx, rpb.NewServerReflectionClient(cc))
+	defer refClient.Reset()
+
+	args := flag.Args()[1:]
+	switch flag.Arg(0) {
+	case "list":
+		if len(args) > 1 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		err = list(refClient, args)
+	case "call":
+		if len(args) < 1 || len(args) > 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		err = call(ctx, cc, refClient, args)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(flushCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot flush the spans: %v\n", err)
+	}
+	cancel()
+	if err != nil {
+		fatalf("%v", err)
+	}
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
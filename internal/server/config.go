@@ -0,0 +1,235 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/naraycitra/grpc-go-adventure/internal/blog/blogserver"
+	"github.com/naraycitra/grpc-go-adventure/internal/logging"
+	"github.com/naraycitra/grpc-go-adventure/internal/ratelimit"
+	"github.com/naraycitra/grpc-go-adventure/internal/tlsconfig"
+	"github.com/naraycitra/grpc-go-adventure/internal/tracing"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	serviceGreet      = "greet"
+	serviceCalculator = "calculator"
+	serviceBlog       = "blog"
+
+	// envPrefix is prepended to the upper cased flag names to get their environment variable,
+	// -mongo-uri is read from SERVER_MONGO_URI
+	envPrefix = "SERVER_"
+)
+
+// config is the configuration of the server. Every setting comes from, in order of
+// precedence, its flag, its environment variable, the config file or the default.
+type config struct {
+	// Listen is the address the gRPC server listens on
+	Listen string `yaml:"listen"`
+	// HTTPListen is the address of the HTTP/JSON gateway of the BlogService, empty to disable it
+	HTTPListen string `yaml:"http_listen"`
+	// MetricsListen is the address serving the Prometheus metrics on /metrics, empty to disable it
+	MetricsListen string `yaml:"metrics_listen"`
+	// Services are the services registered on the server
+	Services []string `yaml:"services"`
+	// HealthInterval is how often the dependencies of the services are probed
+	HealthInterval time.Duration `yaml:"health_interval"`
+	// ShutdownTimeout is how long the calls in flight may take to finish once a
+	// stop is requested before they are cancelled
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// DrainDelay is how long the server keeps serving once its health turned
+	// NOT_SERVING on a stop, so load balancers notice it before it stops
+	// accepting calls. It counts against ShutdownTimeout.
+	DrainDelay time.Duration `yaml:"drain_delay"`
+	// Log configures the level and the format of the server logs
+	Log logging.Config `yaml:"log"`
+	// TLS secures the gRPC server, the HTTP gateway and the metrics endpoint
+	TLS  tlsconfig.ServerConfig `yaml:"tls"`
+	Auth authConfig             `yaml:"auth"`
+	// RateLimit limits the calls of every client to the methods it lists
+	RateLimit ratelimit.Config  `yaml:"rate_limit"`
+	Tracing   tracing.Config    `yaml:"tracing"`
+	Blog      blogserver.Config `yaml:"blog"`
+}
+
+type authConfig struct {
+	JWTHMACKey string `yaml:"jwt_hmac_key"`
+	JWTRSAKey  string `yaml:"jwt_rsa_key"`
+	RBACPolicy string `yaml:"rbac_policy"`
+}
+
+func defaultConfig() config {
+	return config{
+		Listen:          ":50051",
+		HTTPListen:      ":8080",
+		MetricsListen:   ":9090",
+		Services:        []string{serviceGreet, serviceCalculator, serviceBlog},
+		HealthInterval:  10 * time.Second,
+		ShutdownTimeout: 30 * time.Second,
+		DrainDelay:      5 * time.Second,
+		Log:             logging.DefaultConfig(),
+		TLS:             tlsconfig.ServerConfig{ReloadInterval: time.Minute},
+		RateLimit:       ratelimit.DefaultConfig(),
+		Tracing:         tracing.DefaultConfig(),
+		Blog:            blogserver.DefaultConfig(),
+	}
+}
+
+// enabled reports whether the service is registered on the server
+func (c *config) enabled(service string) bool {
+	for _, s := range c.Services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *config) validate() error {
+	if len(c.Services) == 0 {
+		return fmt.Errorf("no service enabled")
+	}
+	for _, s := range c.Services {
+		switch s {
+		case serviceGreet, serviceCalculator, serviceBlog:
+		default:
+			return fmt.Errorf("unknown service %q", s)
+		}
+	}
+	if c.HealthInterval <= 0 {
+		return fmt.Errorf("health interval must be positive")
+	}
+	if c.ShutdownTimeout <= 0 {
+		return fmt.Errorf("shutdown timeout must be positive")
+	}
+	if c.DrainDelay < 0 || c.DrainDelay >= c.ShutdownTimeout {
+		return fmt.Errorf("drain delay must be at least 0 and shorter than the shutdown timeout")
+	}
+	if err := c.Log.Validate(); err != nil {
+		return err
+	}
+	if err := c.TLS.Validate(); err != nil {
+		return err
+	}
+	if c.Auth.RBACPolicy != "" && c.Auth.JWTHMACKey == "" && c.Auth.JWTRSAKey == "" {
+		return fmt.Errorf("an access policy needs authentication, set -jwt-hmac-key or -jwt-rsa-key")
+	}
+	if c.enabled(serviceBlog) {
+		if err := c.Blog.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := c.RateLimit.Validate(); err != nil {
+		return err
+	}
+	return c.Tracing.Validate()
+}
+
+// listValue is a comma separated flag.Value
+type listValue struct {
+	list *[]string
+}
+
+func (v listValue) String() string {
+	if v.list == nil {
+		return ""
+	}
+	return strings.Join(*v.list, ",")
+}
+
+func (v listValue) Set(s string) error {
+	*v.list = nil
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*v.list = append(*v.list, item)
+		}
+	}
+	return nil
+}
+
+// loadConfig builds the configuration from the command line arguments, the
+// environment and the config file named by -config
+func loadConfig(args []string) (*config, error) {
+	cfg := defaultConfig()
+	var configFile string
+
+	fs := flag.NewFlagSet("server", flag.ExitOnError)
+	fs.StringVar(&configFile, "config", "", "YAML config file")
+	fs.StringVar(&cfg.Listen, "listen", cfg.Listen, "address to listen on")
+	fs.StringVar(&cfg.HTTPListen, "http-listen", cfg.HTTPListen, "address of the HTTP/JSON gateway of the blog service, empty to disable it")
+	fs.StringVar(&cfg.MetricsListen, "metrics-listen", cfg.MetricsListen, "address serving the Prometheus metrics on /metrics, empty to disable it")
+	fs.Var(listValue{&cfg.Services}, "services", "comma separated services to serve: greet, calculator, blog")
+	fs.DurationVar(&cfg.HealthInterval, "health-interval", cfg.HealthInterval, "how often the dependencies of the services are health checked")
+	fs.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", cfg.ShutdownTimeout, "how long the calls in flight may take to finish on SIGINT or SIGTERM before they are cancelled")
+	fs.DurationVar(&cfg.DrainDelay, "drain-delay", cfg.DrainDelay, "how long the server keeps serving after reporting NOT_SERVING on SIGINT or SIGTERM, part of the shutdown timeout")
+	fs.StringVar(&cfg.RateLimit.QuotaFile, "quota-file", cfg.RateLimit.QuotaFile, "file keeping the daily quota usage across restarts")
+	fs.StringVar(&cfg.Log.Level, "log-level", cfg.Log.Level, "minimum level logged: debug, info, warn or error")
+	fs.StringVar(&cfg.Log.Format, "log-format", cfg.Log.Format, "log output: console or json")
+	fs.StringVar(&cfg.TLS.CertFile, "tls-cert", "", "PEM certificate of the server, enables TLS")
+	fs.StringVar(&cfg.TLS.KeyFile, "tls-key", "", "PEM private key of the server certificate")
+	fs.StringVar(&cfg.TLS.ClientCAFile, "tls-client-ca", "", "PEM bundle of the CAs verifying client certificates, enables mutual TLS")
+	fs.BoolVar(&cfg.TLS.RequireClientCert, "tls-require-client-cert", false, "reject the clients without a certificate")
+	fs.DurationVar(&cfg.TLS.ReloadInterval, "tls-reload-interval", cfg.TLS.ReloadInterval, "how often the TLS files are checked for changes")
+	fs.StringVar(&cfg.Auth.JWTHMACKey, "jwt-hmac-key", "", "file holding the shared secret verifying HS256/384/512 bearer tokens")
+	fs.StringVar(&cfg.Auth.JWTRSAKey, "jwt-rsa-key", "", "PEM file holding the RSA public key verifying RS256/384/512 bearer tokens")
+	fs.StringVar(&cfg.Auth.RBACPolicy, "rbac-policy", "", "YAML file granting roles access to methods, reloaded on SIGHUP")
+	fs.StringVar(&cfg.Tracing.Exporter, "trace-exporter", cfg.Tracing.Exporter, "where spans are exported: none, stdout or otlp")
+	fs.StringVar(&cfg.Tracing.File, "trace-file", "", "file the stdout trace exporter appends to instead of stderr")
+	fs.StringVar(&cfg.Tracing.OTLPEndpoint, "trace-otlp-endpoint", "", "host:port of the OTLP/HTTP collector, defaults to $OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318")
+	fs.BoolVar(&cfg.Tracing.OTLPInsecure, "trace-otlp-insecure", false, "send the spans to the collector over plain HTTP")
+	fs.Float64Var(&cfg.Tracing.SampleRatio, "trace-sample-ratio", cfg.Tracing.SampleRatio, "fraction of the traces started by the server that are recorded")
+	fs.StringVar(&cfg.Blog.Store, "store", cfg.Blog.Store, "blog store backend: mongo or memory")
+	fs.StringVar(&cfg.Blog.MongoURI, "mongo-uri", cfg.Blog.MongoURI, "MongoDB connection URI")
+	fs.DurationVar(&cfg.Blog.TrashRetention, "trash-retention", cfg.Blog.TrashRetention, "how long deleted blogs stay in the trash before being purged")
+	fs.DurationVar(&cfg.Blog.PurgeInterval, "purge-interval", cfg.Blog.PurgeInterval, "how often the trash is checked for blogs to purge")
+	fs.IntVar(&cfg.Blog.WatchReplay, "watch-replay", cfg.Blog.WatchReplay, "how many blog events are kept for WatchBlogs clients to resume from")
+	fs.Parse(args)
+
+	// the flags were parsed into cfg to find the config file, now that it is
+	// known start over from the lowest precedence
+	explicit := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = f.Value.String()
+	})
+	if configFile == "" {
+		configFile = os.Getenv(envPrefix + "CONFIG")
+	}
+
+	cfg = defaultConfig()
+	if configFile != "" {
+		b, err := ioutil.ReadFile(configFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.UnmarshalStrict(b, &cfg); err != nil {
+			return nil, fmt.Errorf("cannot parse config %s: %v", configFile, err)
+		}
+	}
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		name := envPrefix + strings.ToUpper(strings.Replace(f.Name, "-", "_", -1))
+		value, ok := os.LookupEnv(name)
+		if !ok || f.Name == "config" || err != nil {
+			return
+		}
+		if setErr := f.Value.Set(value); setErr != nil {
+			err = fmt.Errorf("invalid %s: %v", name, setErr)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range explicit {
+		fs.Set(name, value)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
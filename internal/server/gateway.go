@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"github.com/golang/protobuf/proto"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/naraycitra/grpc-go-adventure/internal/blog/blogpb"
+	"github.com/naraycitra/grpc-go-adventure/internal/logging"
+	"github.com/naraycitra/grpc-go-adventure/internal/ratelimit"
+	"github.com/naraycitra/grpc-go-adventure/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// newGateway returns the HTTP/JSON gateway of the BlogService. It calls the gRPC
+// server listening on grpcAddr with the dial options, which must set the transport,
+// so requests go through the same interceptors.
+func newGateway(ctx context.Context, grpcAddr net.Addr, dialOpts ...grpc.DialOption) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithForwardResponseOption(createdStatus),
+		runtime.WithIncomingHeaderMatcher(incomingHeader),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeader),
+	)
+	opts := append(dialOpts, grpc.WithStatsHandler(tracing.NewClientHandler()))
+	err := blogpb.RegisterBlogServiceHandlerFromEndpoint(ctx, mux, dialAddr(grpcAddr), opts)
+	if err != nil {
+		return nil, err
+	}
+	return traceContext(mux), nil
+}
+
+// traceContext continues the trace of the HTTP caller given in the W3C traceparent header
+func traceContext(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// incomingHeader forwards the X-Request-Id header to the gRPC server on top of the
+// headers forwarded by default, but never the secret of the gateway calls
+func incomingHeader(key string) (string, bool) {
+	if strings.EqualFold(key, logging.RequestIDHeader) {
+		return logging.RequestIDHeader, true
+	}
+	if strings.EqualFold(key, runtime.MetadataHeaderPrefix+ratelimit.ProxyKeyHeader) {
+		return "", false
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// outgoingHeader answers the request id in X-Request-Id, the other gRPC headers
+// keep the Grpc-Metadata- prefix
+func outgoingHeader(key string) (string, bool) {
+	if key == logging.RequestIDHeader {
+		return http.CanonicalHeaderKey(key), true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// createdStatus answers 201 Created instead of 200 OK to POST /v1/blogs
+func createdStatus(ctx context.Context, w http.ResponseWriter, resp proto.Message) error {
+	if _, ok := resp.(*blogpb.CreateBlogResponse); ok {
+		w.WriteHeader(http.StatusCreated)
+	}
+	return nil
+}
+
+// dialAddr returns the address to dial to reach a local listener, which may listen
+// on every interface
+func dialAddr(addr net.Addr) string {
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok || !tcp.IP.IsUnspecified() {
+		return addr.String()
+	}
+	return net.JoinHostPort("localhost", strconv.Itoa(tcp.Port))
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"strings"
+	"time"
+)
+
+// healthServicePrefix starts the methods of the grpc.health.v1.Health service
+const healthServicePrefix = "/grpc.health.v1.Health/"
+
+// healthCheck returns an error when its service cannot serve requests
+type healthCheck func(ctx context.Context) error
+
+// runHealthProbe runs the checks every interval until ctx is done and reports their
+// services NOT_SERVING while they fail. The overall health, the empty service name,
+// is SERVING only when every service is.
+func runHealthProbe(ctx context.Context, hs *health.Server, checks map[string]healthCheck, interval time.Duration, sugar *zap.SugaredLogger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	failing := make(map[string]bool)
+	for {
+		overall := healthpb.HealthCheckResponse_SERVING
+		for service, check := range checks {
+			checkCtx, cancel := context.WithTimeout(ctx, interval)
+			err := check(checkCtx)
+			cancel()
+			if ctx.Err() != nil {
+				return
+			}
+
+			servingStatus := healthpb.HealthCheckResponse_SERVING
+			if err != nil {
+				servingStatus = healthpb.HealthCheckResponse_NOT_SERVING
+				overall = healthpb.HealthCheckResponse_NOT_SERVING
+			}
+			// only log changes, a probe every few seconds would flood the logs
+			if err != nil && !failing[service] {
+				sugar.Errorf("%s is not serving: %v", service, err)
+			} else if err == nil && failing[service] {
+				sugar.Infof("%s is serving again", service)
+			}
+			failing[service] = err != nil
+			hs.SetServingStatus(service, servingStatus)
+		}
+		hs.SetServingStatus("", overall)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// skipHealthUnary does not run interceptor for the health service, whose
+// probes carry no credentials
+func skipHealthUnary(interceptor grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(ctx, req)
+		}
+		return interceptor(ctx, req, info, handler)
+	}
+}
+
+// skipHealthStream is the streaming counterpart of skipHealthUnary
+func skipHealthStream(interceptor grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(srv, ss)
+		}
+		return interceptor(srv, ss, info, handler)
+	}
+}
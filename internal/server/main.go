@@ -0,0 +1,296 @@
+// Command server serves any subset of the GreetService, CalculatorService and
+// BlogService on a single gRPC server.
+package main
+
+import (
+	"context"
+	"fmt"
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
+	"github.com/naraycitra/grpc-go-adventure/internal/auth"
+	"github.com/naraycitra/grpc-go-adventure/internal/blog/blogserver"
+	"github.com/naraycitra/grpc-go-adventure/internal/calculator/calculatorpb"
+	"github.com/naraycitra/grpc-go-adventure/internal/calculator/calculatorserver"
+	"github.com/naraycitra/grpc-go-adventure/internal/greet/greetpb"
+	"github.com/naraycitra/grpc-go-adventure/internal/greet/greetserver"
+	"github.com/naraycitra/grpc-go-adventure/internal/logging"
+	"github.com/naraycitra/grpc-go-adventure/internal/metrics"
+	"github.com/naraycitra/grpc-go-adventure/internal/ratelimit"
+	"github.com/naraycitra/grpc-go-adventure/internal/rbac"
+	"github.com/naraycitra/grpc-go-adventure/internal/tlsconfig"
+	"github.com/naraycitra/grpc-go-adventure/internal/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	cfg, err := loadConfig(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration: %v\n", err)
+		os.Exit(2)
+	}
+	logger, err := logging.NewLogger(cfg.Log)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot create the logger: %v\n", err)
+		os.Exit(2)
+	}
+	defer logger.Sync()
+	// the handlers log outside of requests through the global logger
+	zap.ReplaceGlobals(logger)
+	sugar := logger.Sugar()
+	sugar.Infof("Server starting with services %v", cfg.Services)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, "server")
+	if err != nil {
+		sugar.Fatalf("failed to set up tracing: %v", err)
+	}
+
+	// metrics and logging come first to also see the calls rejected by the other
+	// interceptors, then the recovery of the panics in the rest of the chain and the handlers
+	serverMetrics := metrics.NewServerMetrics()
+	prometheus.MustRegister(serverMetrics)
+	unary := []grpc.UnaryServerInterceptor{
+		serverMetrics.UnaryServerInterceptor(),
+		logging.UnaryServerInterceptor(logger),
+		grpc_recovery.UnaryServerInterceptor(recoveryOption()),
+	}
+	stream := []grpc.StreamServerInterceptor{
+		serverMetrics.StreamServerInterceptor(),
+		logging.StreamServerInterceptor(logger),
+		grpc_recovery.StreamServerInterceptor(recoveryOption()),
+	}
+	if cfg.Auth.JWTHMACKey != "" || cfg.Auth.JWTRSAKey != "" {
+		authenticator, err := auth.NewAuthenticator(cfg.Auth.JWTHMACKey, cfg.Auth.JWTRSAKey)
+		if err != nil {
+			sugar.Fatalf("failed to load token keys: %v", err)
+		}
+		unary = append(unary, skipHealthUnary(auth.UnaryServerInterceptor(authenticator)))
+		stream = append(stream, skipHealthStream(auth.StreamServerInterceptor(authenticator)))
+	} else {
+		sugar.Warn("No token key configured, authentication is disabled")
+	}
+	if cfg.Auth.RBACPolicy != "" {
+		enforcer, err := rbac.NewEnforcer(cfg.Auth.RBACPolicy, sugar)
+		if err != nil {
+			sugar.Fatalf("failed to load access policy: %v", err)
+		}
+		defer enforcer.ReloadOnSIGHUP()()
+		unary = append(unary, skipHealthUnary(enforcer.UnaryServerInterceptor()))
+		stream = append(stream, skipHealthStream(enforcer.StreamServerInterceptor()))
+	}
+	// only the calls the client may make count towards its limits
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled() {
+		if limiter, err = ratelimit.NewLimiter(cfg.RateLimit, sugar); err != nil {
+			sugar.Fatalf("failed to set up rate limiting: %v", err)
+		}
+		unary = append(unary, skipHealthUnary(limiter.UnaryServerInterceptor()))
+		stream = append(stream, skipHealthStream(limiter.StreamServerInterceptor()))
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.StatsHandler(tracing.NewServerHandler()),
+		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(unary...)),
+		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(stream...)),
+	}
+	// the gateway dials the gRPC server like any client
+	gatewayTransport := grpc.WithInsecure()
+	var tlsServer *tlsconfig.Server
+	if cfg.TLS.Enabled() {
+		if tlsServer, err = tlsconfig.NewServer(cfg.TLS); err != nil {
+			sugar.Fatalf("failed to set up TLS: %v", err)
+		}
+		defer tlsServer.WatchFiles(sugar)()
+		opts = append(opts, grpc.Creds(tlsServer.Credentials()))
+		gatewayTransport = grpc.WithTransportCredentials(tlsServer.LoopbackCredentials())
+	} else {
+		sugar.Warn("No TLS certificate configured, serving in plaintext")
+	}
+	s := grpc.NewServer(opts...)
+	// lets generic clients like the rpc command discover the services
+	reflection.Register(s)
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(s, healthServer)
+	// the services without dependencies serve as long as the server does,
+	// the others are probed
+	checks := make(map[string]healthCheck)
+	if cfg.enabled(serviceGreet) {
+		greetpb.RegisterGreetServiceServer(s, greetserver.New())
+		healthServer.SetServingStatus("greet.GreetService", healthpb.HealthCheckResponse_SERVING)
+	}
+	if cfg.enabled(serviceCalculator) {
+		calculatorpb.RegisterCalculatorServiceServer(s, calculatorserver.New())
+		healthServer.SetServingStatus("calculatorpb.CalculatorService", healthpb.HealthCheckResponse_SERVING)
+	}
+	var blog *blogserver.Service
+	if cfg.enabled(serviceBlog) {
+		if blog, err = blogserver.New(cfg.Blog, sugar.Named("blog")); err != nil {
+			sugar.Fatalf("failed to start the blog service: %v", err)
+		}
+		blog.Register(s)
+		prometheus.MustRegister(blog)
+		checks["blog.BlogService"] = blog.Check
+	}
+	serverMetrics.InitializeMetrics(s)
+	probeCtx, stopProbe := context.WithCancel(context.Background())
+	go runHealthProbe(probeCtx, healthServer, checks, cfg.HealthInterval, sugar)
+
+	lis, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		sugar.Fatalf("Failed to listen: %v", err)
+	}
+
+	go func() {
+		sugar.Infof("Serving on %s", lis.Addr())
+		if err := s.Serve(lis); err != nil {
+			sugar.Errorf("error to server: %v", err)
+		}
+	}()
+
+	var httpServer *http.Server
+	if blog != nil && cfg.HTTPListen != "" {
+		gatewayOpts := []grpc.DialOption{gatewayTransport}
+		if limiter != nil {
+			// the calls of the gateway are limited by the address of its clients
+			gatewayOpts = append(gatewayOpts, grpc.WithPerRPCCredentials(limiter.ProxyCredentials()))
+		}
+		gateway, err := newGateway(context.Background(), lis.Addr(), gatewayOpts...)
+		if err != nil {
+			sugar.Fatalf("failed to start the HTTP gateway: %v", err)
+		}
+		httpServer = &http.Server{Addr: cfg.HTTPListen, Handler: gateway}
+		go func() {
+			sugar.Infof("Serving HTTP on %s", cfg.HTTPListen)
+			if err := listenAndServe(httpServer, tlsServer); err != http.ErrServerClosed {
+				sugar.Fatalf("error to serve HTTP: %v", err)
+			}
+		}()
+	}
+
+	var metricsServer *http.Server
+	if cfg.MetricsListen != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		metricsServer = &http.Server{Addr: cfg.MetricsListen, Handler: mux}
+		go func() {
+			sugar.Infof("Serving metrics on %s", cfg.MetricsListen)
+			if err := listenAndServe(metricsServer, tlsServer); err != http.ErrServerClosed {
+				sugar.Fatalf("error to serve metrics: %v", err)
+			}
+		}()
+	}
+
+	// rolling restarts and Ctrl+C stop the server gracefully, a second signal
+	// stops it right away
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	sig := <-signals
+	sugar.Infof("Received %v, stopping the server", sig)
+
+	// load balancers and orchestrators stop sending new calls to the server while
+	// the calls in flight finish
+	stopProbe()
+	healthServer.Shutdown()
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	drain(ctx, cfg.DrainDelay, signals, sugar)
+	if blog != nil {
+		blog.StopWatchers()
+	}
+	// the gateway goes first since its requests are calls to the gRPC server
+	if httpServer != nil {
+		shutdownHTTP(ctx, httpServer, "HTTP gateway", sugar)
+	}
+	gracefulStop(ctx, s, signals, sugar)
+	if limiter != nil {
+		if err := limiter.Close(); err != nil {
+			sugar.Errorf("failed to save the quota usage: %v", err)
+		}
+	}
+	if metricsServer != nil {
+		shutdownHTTP(ctx, metricsServer, "metrics server", sugar)
+	}
+	// the handlers no longer use the database
+	if blog != nil {
+		blog.Close()
+	}
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(flushCtx); err != nil {
+		sugar.Errorf("failed to flush the spans: %v", err)
+	}
+	cancelFlush()
+	sugar.Info("Server stopped")
+}
+
+// drain keeps serving for delay so that the health checkers see the server is
+// NOT_SERVING before it stops accepting calls. Another signal stops waiting and
+// the next steps right away.
+func drain(ctx context.Context, delay time.Duration, signals chan os.Signal, sugar *zap.SugaredLogger) {
+	if delay <= 0 {
+		return
+	}
+	sugar.Infof("Draining for %v before stopping", delay)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	case sig := <-signals:
+		sugar.Warnf("Received %v again, stopping without draining", sig)
+		// leave the signal to gracefulStop, which cancels the calls in flight
+		select {
+		case signals <- sig:
+		default:
+		}
+	}
+}
+
+// gracefulStop stops s once the calls in flight are finished, or cancels them
+// when ctx is done or another signal is received first
+func gracefulStop(ctx context.Context, s *grpc.Server, signals <-chan os.Signal, sugar *zap.SugaredLogger) {
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return
+	case <-ctx.Done():
+		sugar.Warn("Calls still in flight after the shutdown timeout, cancelling them")
+	case sig := <-signals:
+		sugar.Warnf("Received %v again, cancelling the calls in flight", sig)
+	}
+	s.Stop()
+	<-stopped
+}
+
+// shutdownHTTP stops srv once its requests are answered, or closes their
+// connections when ctx is done first
+func shutdownHTTP(ctx context.Context, srv *http.Server, name string, sugar *zap.SugaredLogger) {
+	if err := srv.Shutdown(ctx); err != nil {
+		sugar.Warnf("Closing the %s with requests in flight: %v", name, err)
+		srv.Close()
+	}
+}
+
+// listenAndServe serves srv over TLS with the certificates of tlsServer, or in
+// plaintext when it is nil
+func listenAndServe(srv *http.Server, tlsServer *tlsconfig.Server) error {
+	if tlsServer == nil {
+		return srv.ListenAndServe()
+	}
+	srv.TLSConfig = tlsServer.TLSConfig("h2", "http/1.1")
+	return srv.ListenAndServeTLS("", "")
+}
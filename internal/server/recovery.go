@@ -0,0 +1,23 @@
+package main
+
+import (
+	"context"
+	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
+	"github.com/naraycitra/grpc-go-adventure/internal/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"runtime/debug"
+)
+
+// recoveryOption turns a panic of a handler into an INTERNAL error for its caller
+// only, logging the stack to the request logger so the bug can be found. The panic
+// value is not sent back since it may reveal internals.
+func recoveryOption() grpc_recovery.Option {
+	return grpc_recovery.WithRecoveryHandlerContext(func(ctx context.Context, p interface{}) error {
+		// still on the panicking goroutine, so the stack leads to the panic
+		method, _ := grpc.Method(ctx)
+		logging.FromContext(ctx).Errorf("panic in %s: %v\n%s", method, p, debug.Stack())
+		return status.Error(codes.Internal, "internal error")
+	})
+}
@@ -0,0 +1,46 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"fmt"
+	"google.golang.org/grpc/credentials"
+)
+
+// ClientConfig configures TLS on a client connection
+type ClientConfig struct {
+	// CAFile holds the CAs the server certificate is verified against, the system
+	// roots are used when empty
+	CAFile string
+	// CertFile and KeyFile are the client certificate presented for mutual TLS
+	CertFile string
+	KeyFile  string
+	// ServerName overrides the host name the server certificate is verified for,
+	// which defaults to the host of the dialed address
+	ServerName string
+}
+
+// NewClientCredentials returns the transport credentials of a client connection
+func NewClientCredentials(cfg ClientConfig) (credentials.TransportCredentials, error) {
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return nil, fmt.Errorf("a client certificate needs both a certificate and a key file")
+	}
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+	}
+	if cfg.CAFile != "" {
+		pool, err := loadCertPool(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load the client certificate: %v", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(config), nil
+}
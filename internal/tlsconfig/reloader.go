@@ -0,0 +1,132 @@
+// Package tlsconfig builds the TLS transport credentials of the servers and
+// clients, reloading the certificates of long running servers when their files change.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// reloader holds a key pair and an optional CA pool loaded from files, and loads
+// them again when one of the files is modified
+type reloader struct {
+	certFile, keyFile, caFile string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	pool *x509.CertPool
+	// modTimes are the modification times of the files at the last successful load
+	modTimes map[string]time.Time
+}
+
+func newReloader(certFile, keyFile, caFile string) (*reloader, error) {
+	r := &reloader{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *reloader) files() []string {
+	files := []string{r.certFile, r.keyFile}
+	if r.caFile != "" {
+		files = append(files, r.caFile)
+	}
+	return files
+}
+
+// reload loads the files again if any of them changed since the last load and
+// reports whether it did. The current certificates stay in use on error.
+func (r *reloader) reload() (bool, error) {
+	modTimes := make(map[string]time.Time)
+	changed := false
+	r.mu.RLock()
+	for _, f := range r.files() {
+		info, err := os.Stat(f)
+		if err != nil {
+			r.mu.RUnlock()
+			return false, err
+		}
+		modTimes[f] = info.ModTime()
+		// compare for equality, a file replaced by an older copy changed as well
+		if !info.ModTime().Equal(r.modTimes[f]) {
+			changed = true
+		}
+	}
+	r.mu.RUnlock()
+	if !changed {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, err
+	}
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		if pool, err = loadCertPool(r.caFile); err != nil {
+			return false, err
+		}
+	}
+
+	r.mu.Lock()
+	r.cert, r.pool, r.modTimes = &cert, pool, modTimes
+	r.mu.Unlock()
+	return true, nil
+}
+
+// watch checks the files for changes every interval until stop is called
+func (r *reloader) watch(interval time.Duration, logger *zap.SugaredLogger) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				reloaded, err := r.reload()
+				if err != nil {
+					logger.Errorf("Cannot reload the TLS certificates, keeping the current ones: %v", err)
+				} else if reloaded {
+					logger.Infof("Reloaded the TLS certificates from %v", r.files())
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+	}
+}
+
+func (r *reloader) certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+func (r *reloader) certPool() *x509.CertPool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.pool
+}
+
+// loadCertPool reads a PEM bundle of CA certificates
+func loadCertPool(filename string) (*x509.CertPool, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no certificate found in %s", filename)
+	}
+	return pool, nil
+}
@@ -0,0 +1,176 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// keyPair is a PEM encoded self-signed certificate and its key
+type keyPair struct {
+	cert, key []byte
+}
+
+func newKeyPair(t *testing.T, commonName string) keyPair {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keyPair{
+		cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		key:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// writeFile replaces filename with b and moves its modification time forward,
+// since several writes may happen within the resolution of the file system
+func writeFile(t *testing.T, filename string, b []byte, modTime time.Time) {
+	t.Helper()
+	if err := ioutil.WriteFile(filename, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(filename, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func commonName(t *testing.T, cert *tls.Certificate) string {
+	t.Helper()
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.Subject.CommonName
+}
+
+func TestServerReloadsCertificates(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	modTime := time.Now().Add(-time.Hour)
+	first := newKeyPair(t, "first")
+	writeFile(t, certFile, first.cert, modTime)
+	writeFile(t, keyFile, first.key, modTime)
+
+	s, err := NewServer(ServerConfig{CertFile: certFile, KeyFile: keyFile, ReloadInterval: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := s.TLSConfig("h2")
+	served := func() string {
+		cert, err := config.GetCertificate(&tls.ClientHelloInfo{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		perClient, err := config.GetConfigForClient(&tls.ClientHelloInfo{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name := commonName(t, &perClient.Certificates[0]); name != commonName(t, cert) {
+			t.Errorf("GetConfigForClient() serves %s while GetCertificate() serves %s", name, commonName(t, cert))
+		}
+		return commonName(t, cert)
+	}
+	if got := served(); got != "first" {
+		t.Fatalf("served certificate %s, want first", got)
+	}
+
+	second, third := newKeyPair(t, "second"), newKeyPair(t, "third")
+	tests := []struct {
+		name string
+		// change updates the files, nil leaves them alone
+		change       func(modTime time.Time)
+		wantReloaded bool
+		wantErr      bool
+		wantServed   string
+	}{
+		{"unchanged files", nil, false, false, "first"},
+		{"invalid certificate", func(modTime time.Time) {
+			writeFile(t, certFile, []byte("not a certificate"), modTime)
+		}, false, true, "first"},
+		{"key of another certificate", func(modTime time.Time) {
+			writeFile(t, certFile, second.cert, modTime)
+			writeFile(t, keyFile, third.key, modTime)
+		}, false, true, "first"},
+		{"missing key", func(modTime time.Time) {
+			if err := os.Remove(keyFile); err != nil {
+				t.Fatal(err)
+			}
+		}, false, true, "first"},
+		{"valid key pair", func(modTime time.Time) {
+			writeFile(t, certFile, second.cert, modTime)
+			writeFile(t, keyFile, second.key, modTime)
+		}, true, false, "second"},
+		{"unchanged after a reload", nil, false, false, "second"},
+		{"older copy of the files", func(modTime time.Time) {
+			writeFile(t, certFile, first.cert, modTime.Add(-time.Hour))
+			writeFile(t, keyFile, first.key, modTime.Add(-time.Hour))
+		}, true, false, "first"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.change != nil {
+				tt.change(modTime.Add(time.Duration(i+1) * time.Minute))
+			}
+			reloaded, err := s.files.reload()
+			if (err != nil) != tt.wantErr || reloaded != tt.wantReloaded {
+				t.Errorf("reload() = %v, %v, want reloaded %v and an error %v", reloaded, err, tt.wantReloaded, tt.wantErr)
+			}
+			if got := served(); got != tt.wantServed {
+				t.Errorf("served certificate %s, want %s", got, tt.wantServed)
+			}
+		})
+	}
+}
+
+func TestWatchReloadsCertificates(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	modTime := time.Now().Add(-time.Hour)
+	first, second := newKeyPair(t, "first"), newKeyPair(t, "second")
+	writeFile(t, certFile, first.cert, modTime)
+	writeFile(t, keyFile, first.key, modTime)
+
+	r, err := newReloader(certFile, keyFile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := r.watch(time.Millisecond, zap.NewNop().Sugar())
+	defer stop()
+
+	writeFile(t, certFile, second.cert, modTime.Add(time.Minute))
+	writeFile(t, keyFile, second.key, modTime.Add(time.Minute))
+	deadline := time.Now().Add(5 * time.Second)
+	for commonName(t, r.certificate()) != "second" {
+		if time.Now().After(deadline) {
+			t.Fatal("watch() did not reload the changed certificate")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,125 @@
+package tlsconfig
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/credentials"
+	"time"
+)
+
+// ServerConfig configures TLS on a server. TLS is disabled when CertFile is empty.
+type ServerConfig struct {
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	// ClientCAFile holds the CAs client certificates are verified against, which
+	// enables mutual TLS
+	ClientCAFile string `yaml:"client_ca_file"`
+	// RequireClientCert rejects the clients presenting no certificate, otherwise
+	// only the certificates presented are verified
+	RequireClientCert bool `yaml:"require_client_cert"`
+	// ReloadInterval is how often the files are checked for changes
+	ReloadInterval time.Duration `yaml:"reload_interval"`
+}
+
+// Enabled reports whether the server uses TLS
+func (c *ServerConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+// Validate returns an error when the settings are incomplete or contradictory
+func (c *ServerConfig) Validate() error {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return fmt.Errorf("TLS needs both a certificate and a key file")
+	}
+	if c.ClientCAFile != "" && !c.Enabled() {
+		return fmt.Errorf("client certificates can only be verified with TLS enabled")
+	}
+	if c.RequireClientCert && c.ClientCAFile == "" {
+		return fmt.Errorf("requiring client certificates needs a client CA file")
+	}
+	if c.Enabled() && c.ReloadInterval <= 0 {
+		return fmt.Errorf("TLS reload interval must be positive")
+	}
+	return nil
+}
+
+// Server provides the TLS configuration of a server, always using the latest
+// certificates loaded from the files
+type Server struct {
+	cfg   ServerConfig
+	files *reloader
+}
+
+// NewServer loads the certificates named by cfg
+func NewServer(cfg ServerConfig) (*Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	files, err := newReloader(cfg.CertFile, cfg.KeyFile, cfg.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load the TLS certificates: %v", err)
+	}
+	return &Server{cfg: cfg, files: files}, nil
+}
+
+// WatchFiles reloads the certificates when their files change until stop is called.
+// Connections established before a reload keep their certificates.
+func (s *Server) WatchFiles(logger *zap.SugaredLogger) (stop func()) {
+	return s.files.watch(s.cfg.ReloadInterval, logger)
+}
+
+// TLSConfig returns the configuration of a listener negotiating one of protos with ALPN
+func (s *Server) TLSConfig(protos ...string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: protos,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return s.files.certificate(), nil
+		},
+		// resolved on every handshake to pick up reloaded certificates
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   protos,
+				Certificates: []tls.Certificate{*s.files.certificate()},
+			}
+			if s.cfg.ClientCAFile != "" {
+				config.ClientCAs = s.files.certPool()
+				config.ClientAuth = tls.VerifyClientCertIfGiven
+				if s.cfg.RequireClientCert {
+					config.ClientAuth = tls.RequireAndVerifyClientCert
+				}
+			}
+			return config, nil
+		},
+	}
+}
+
+// Credentials returns the transport credentials of the gRPC server
+func (s *Server) Credentials() credentials.TransportCredentials {
+	return credentials.NewTLS(s.TLSConfig("h2"))
+}
+
+// LoopbackCredentials returns the credentials of a client connection the server
+// makes to itself, like the HTTP gateway does. The server certificate is pinned
+// instead of verified against CAs, and presented as client certificate, so under
+// mutual TLS it must allow client authentication.
+func (s *Server) LoopbackCredentials() credentials.TransportCredentials {
+	return credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// replaced by the comparison with the local certificate below
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 || !bytes.Equal(rawCerts[0], s.files.certificate().Certificate[0]) {
+				return fmt.Errorf("the server certificate is not the local one")
+			}
+			return nil
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return s.files.certificate(), nil
+		},
+	})
+}
@@ -0,0 +1,108 @@
+package tracing
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+const instrumentationName = "github.com/naraycitra/grpc-go-adventure/internal/tracing"
+
+var (
+	messageTypeKey = attribute.Key("message.type")
+	messageSizeKey = attribute.Key("message.uncompressed_size")
+)
+
+// metadataCarrier lets the propagator read and write the trace context in gRPC metadata
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if v := metadata.MD(c).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// statsHandler traces every RPC of a client or a server in a span, with an event per message
+type statsHandler struct {
+	kind trace.SpanKind
+}
+
+// NewServerHandler returns a grpc.StatsHandler server option value continuing the
+// trace of the caller, so the handlers can start child spans from their context
+func NewServerHandler() stats.Handler {
+	return &statsHandler{kind: trace.SpanKindServer}
+}
+
+// NewClientHandler returns a grpc.WithStatsHandler dial option value sending the
+// trace context of the calls to the server
+func NewClientHandler() stats.Handler {
+	return &statsHandler{kind: trace.SpanKindClient}
+}
+
+func (h *statsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	propagator := otel.GetTextMapPropagator()
+	if h.kind == trace.SpanKindServer {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = propagator.Extract(ctx, metadataCarrier(md))
+	}
+
+	name := strings.TrimPrefix(info.FullMethodName, "/")
+	attrs := []attribute.KeyValue{semconv.RPCSystemKey.String("grpc")}
+	if i := strings.Index(name, "/"); i >= 0 {
+		attrs = append(attrs, semconv.RPCServiceKey.String(name[:i]), semconv.RPCMethodKey.String(name[i+1:]))
+	}
+	ctx, _ = otel.Tracer(instrumentationName).Start(ctx, name,
+		trace.WithSpanKind(h.kind),
+		trace.WithAttributes(attrs...))
+
+	if h.kind == trace.SpanKindClient {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		md = md.Copy()
+		propagator.Inject(ctx, metadataCarrier(md))
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+	return ctx
+}
+
+func (h *statsHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	span := trace.SpanFromContext(ctx)
+	switch rs := rs.(type) {
+	case *stats.InPayload:
+		span.AddEvent("message", trace.WithAttributes(messageTypeKey.String("RECEIVED"), messageSizeKey.Int(rs.Length)))
+	case *stats.OutPayload:
+		span.AddEvent("message", trace.WithAttributes(messageTypeKey.String("SENT"), messageSizeKey.Int(rs.Length)))
+	case *stats.End:
+		st, _ := status.FromError(rs.Error)
+		span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(st.Code())))
+		if rs.Error != nil {
+			span.SetStatus(otelcodes.Error, st.Message())
+		}
+		span.End(trace.WithTimestamp(rs.EndTime))
+	}
+}
+
+func (h *statsHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h *statsHandler) HandleConn(ctx context.Context, cs stats.ConnStats) {}
@@ -0,0 +1,118 @@
+// Package tracing sets up OpenTelemetry tracing and propagates the W3C trace
+// context through the metadata of the gRPC calls.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"io"
+	"os"
+)
+
+const (
+	// ExporterNone records no span, the trace context is still propagated
+	ExporterNone = "none"
+	// ExporterStdout writes the spans as JSON to stderr or Config.File, keeping
+	// stdout to the output of the commands
+	ExporterStdout = "stdout"
+	// ExporterOTLP sends the spans to an OpenTelemetry collector over OTLP/HTTP
+	ExporterOTLP = "otlp"
+)
+
+// Config selects where the spans are exported
+type Config struct {
+	// Exporter is none, stdout or otlp
+	Exporter string `yaml:"exporter"`
+	// File receives the spans of the stdout exporter instead of stderr when set
+	File string `yaml:"file"`
+	// OTLPEndpoint is the host:port of the collector, empty to use
+	// $OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318
+	OTLPEndpoint string `yaml:"otlp_endpoint"`
+	// OTLPInsecure sends the spans over plain HTTP
+	OTLPInsecure bool `yaml:"otlp_insecure"`
+	// SampleRatio is the fraction of the traces started here that are recorded,
+	// traces started by a caller follow its decision
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// DefaultConfig returns the configuration used for the settings left unset
+func DefaultConfig() Config {
+	return Config{
+		Exporter:    ExporterNone,
+		SampleRatio: 1,
+	}
+}
+
+// Validate returns an error when the exporter is unknown or the ratio is not a fraction
+func (c *Config) Validate() error {
+	switch c.Exporter {
+	case ExporterNone, ExporterStdout, ExporterOTLP:
+	default:
+		return fmt.Errorf("unknown trace exporter %q", c.Exporter)
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return fmt.Errorf("trace sample ratio must be between 0 and 1")
+	}
+	return nil
+}
+
+// Setup installs the W3C trace context propagator and a tracer provider exporting
+// the spans of serviceName as configured. Shutdown flushes the pending spans.
+func Setup(ctx context.Context, cfg Config, serviceName string) (shutdown func(context.Context) error, err error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var file *os.File
+	switch cfg.Exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		var w io.Writer = os.Stderr
+		if cfg.File != "" {
+			if file, err = os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644); err != nil {
+				return nil, err
+			}
+			w = file
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(w))
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.OTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.OTLPEndpoint))
+		}
+		if cfg.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	}
+	if err != nil {
+		if file != nil {
+			file.Close()
+		}
+		return nil, fmt.Errorf("cannot create the %s trace exporter: %v", cfg.Exporter, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if file != nil {
+			file.Close()
+		}
+		return err
+	}, nil
+}